@@ -0,0 +1,437 @@
+package pgtestkit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// 실패 메시지에 출력할 테이블 내용의 최대 행 수
+const assertMaxRows = 20
+
+// TableOption AssertTableEquals의 비교 방법을 변경하는 옵션입니다.
+type TableOption func(*tableConfig)
+
+// tableConfig AssertTableEquals의 비교 설정입니다.
+type tableConfig struct {
+	columns []string
+	orderBy []string
+}
+
+// WithTableColumns 지정한 컬럼만 비교합니다.
+// 기본값은 기대하는 행에 나온 컬럼이며, 기대하는 행에 없는 컬럼은 NULL로 비교합니다.
+func WithTableColumns(columns ...string) TableOption {
+	return func(c *tableConfig) {
+		c.columns = append(c.columns, columns...)
+	}
+}
+
+// WithTableOrderBy 테이블을 지정한 SQL 식(예: "id", "created_at DESC") 순서로 읽어 행의 순서까지 비교합니다.
+// 지정하지 않으면 행의 순서는 무시합니다.
+func WithTableOrderBy(exprs ...string) TableOption {
+	return func(c *tableConfig) {
+		c.orderBy = append(c.orderBy, exprs...)
+	}
+}
+
+// AssertRowCount table의 행 수가 want인지 검증합니다. 다르면 테이블 내용과 함께 t.Errorf로 보고합니다.
+// table은 "name" 또는 "schema.name" 형식입니다.
+func (h *TestHelper) AssertRowCount(t testing.TB, table string, want int) {
+	t.Helper()
+	rows, ok := h.selectRows(t, table, nil, nil, nil)
+	if !ok {
+		return
+	}
+	if len(rows.values) != want {
+		t.Errorf("pgtestkit: expected %d row(s) in %s, got %d:\n%s", want, table, len(rows.values), rows.render())
+	}
+}
+
+// AssertRowExists where의 모든 컬럼 값이 일치하는 행이 table에 있는지 검증합니다.
+// 값은 컬럼 타입으로 변환하여 비교하며, nil은 NULL과 일치합니다.
+func (h *TestHelper) AssertRowExists(t testing.TB, table string, where map[string]interface{}) {
+	t.Helper()
+	matched, ok := h.selectRows(t, table, where, nil, nil)
+	if !ok || len(matched.values) > 0 {
+		return
+	}
+	all, ok := h.selectRows(t, table, nil, nil, nil)
+	if !ok {
+		return
+	}
+	t.Errorf("pgtestkit: no row in %s matches %s, table contents:\n%s", table, formatWhere(where), all.render())
+}
+
+// AssertNoRows where와 일치하는 행이 table에 없는지 검증합니다. where가 비어 있으면 테이블이 비어 있는지 검증합니다.
+func (h *TestHelper) AssertNoRows(t testing.TB, table string, where map[string]interface{}) {
+	t.Helper()
+	matched, ok := h.selectRows(t, table, where, nil, nil)
+	if !ok || len(matched.values) == 0 {
+		return
+	}
+	if len(where) == 0 {
+		t.Errorf("pgtestkit: expected %s to be empty, got %d row(s):\n%s", table, len(matched.values), matched.render())
+		return
+	}
+	t.Errorf("pgtestkit: expected no rows in %s matching %s, got %d:\n%s",
+		table, formatWhere(where), len(matched.values), matched.render())
+}
+
+// AssertTableEquals table의 내용이 want와 같은지 검증합니다. 다르면 기대하는 행(-)과 실제 행(+)의 차이를
+// 테이블 형태로 보고합니다. 값은 컬럼 타입으로 변환하여 비교하므로 want에는 문자열, 숫자, time.Time 등을 사용할 수 있습니다.
+//
+// 사용 예시:
+//
+//	helper.AssertTableEquals(t, "users", []map[string]interface{}{
+//		{"id": 1, "name": "Alice"},
+//		{"id": 2, "name": "Bob"},
+//	}, pgtestkit.WithTableOrderBy("id"))
+func (h *TestHelper) AssertTableEquals(t testing.TB, table string, want []map[string]interface{}, opts ...TableOption) {
+	t.Helper()
+	var cfg tableConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	db, info, ok := h.describeAssertTable(t, table)
+	if !ok {
+		return
+	}
+
+	columns := cfg.columns
+	if len(columns) == 0 {
+		// 테이블에 정의된 순서로, 기대하는 행에 나온 컬럼만 비교
+		for _, name := range info.order {
+			for _, row := range want {
+				if _, ok := row[name]; ok {
+					columns = append(columns, name)
+					break
+				}
+			}
+		}
+		if len(columns) == 0 {
+			columns = info.order
+		}
+	}
+
+	expected, err := normalizeRows(context.Background(), db, info, columns, want)
+	if err != nil {
+		t.Errorf("pgtestkit: failed to compare %s: %v", table, err)
+		return
+	}
+	actual, ok := h.selectRows(t, table, nil, columns, cfg.orderBy)
+	if !ok {
+		return
+	}
+
+	if len(cfg.orderBy) == 0 {
+		sortRows(expected)
+		sortRows(actual.values)
+	}
+	wantText, gotText := renderTables(columns, expected, actual.values)
+	if diff := diffLines(wantText, gotText); diff != "" {
+		t.Errorf("pgtestkit: %s does not match (- expected, + actual):\n%s", table, diff)
+	}
+}
+
+// assertRows 검증을 위해 읽은 행입니다.
+type assertRows struct {
+	columns []string
+	values  [][]sql.NullString
+}
+
+// render 행을 테이블 형태로 출력합니다. 너무 많으면 일부만 출력합니다.
+func (r assertRows) render() string {
+	values := r.values
+	more := 0
+	if len(values) > assertMaxRows {
+		more = len(values) - assertMaxRows
+		values = values[:assertMaxRows]
+	}
+	text, _ := renderTables(r.columns, values, nil)
+	if more > 0 {
+		text += fmt.Sprintf("... %d more row(s)\n", more)
+	}
+	return text
+}
+
+// assertDB 검증에 사용할 연결을 반환합니다. 처음 호출할 때 연결을 열며, Close에서 닫힙니다.
+// 트랜잭션 격리 모드에서는 테스트 트랜잭션 안의 데이터를 읽습니다.
+func (h *TestHelper) assertDB() (*sql.DB, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.db != nil {
+		return h.db, nil
+	}
+	if h.dbClient == nil || h.dbClient.ConnectionString == "" {
+		return nil, fmt.Errorf("database client is not connected")
+	}
+	driverName := "pgx"
+	if h.dbClient.tx != nil {
+		driverName = TxDriverName
+	}
+	db, err := sql.Open(driverName, h.dbClient.ConnectionString)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open assertion connection: %w", err)
+	}
+	h.db = db
+	return db, nil
+}
+
+// describeAssertTable 검증할 테이블의 카탈로그 정보를 읽고, 실패하면 t.Errorf로 보고합니다.
+func (h *TestHelper) describeAssertTable(t testing.TB, table string) (*sql.DB, *tableInfo, bool) {
+	t.Helper()
+	db, err := h.assertDB()
+	if err != nil {
+		t.Errorf("pgtestkit: %v", err)
+		return nil, nil, false
+	}
+	info, err := describeTable(context.Background(), db, table)
+	if err != nil {
+		t.Errorf("pgtestkit: failed to read %s: %v", table, err)
+		return nil, nil, false
+	}
+	return db, info, true
+}
+
+// selectRows where와 일치하는 행의 columns(비어 있으면 모든 컬럼)를 텍스트로 읽습니다.
+// orderBy가 없으면 컬럼 순서대로 정렬하여 결과를 결정적으로 만듭니다. 실패하면 t.Errorf로 보고합니다.
+func (h *TestHelper) selectRows(t testing.TB, table string, where map[string]interface{}, columns, orderBy []string) (assertRows, bool) {
+	t.Helper()
+	db, info, ok := h.describeAssertTable(t, table)
+	if !ok {
+		return assertRows{}, false
+	}
+	if len(columns) == 0 {
+		columns = info.order
+	}
+
+	selects := make([]string, len(columns))
+	for i, name := range columns {
+		if _, ok := info.columns[name]; !ok {
+			t.Errorf("pgtestkit: column %s does not exist in %s", name, table)
+			return assertRows{}, false
+		}
+		selects[i] = quoteIdentifier(name) + "::text"
+	}
+	query := "SELECT " + strings.Join(selects, ", ") + " FROM " + info.table.quoted()
+
+	// 맵의 순서와 관계없이 같은 쿼리가 되도록 컬럼 이름순으로 조건 생성
+	names := make([]string, 0, len(where))
+	for name := range where {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	conditions := make([]string, 0, len(names))
+	args := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		column, ok := info.columns[name]
+		if !ok {
+			t.Errorf("pgtestkit: column %s does not exist in %s", name, table)
+			return assertRows{}, false
+		}
+		arg, err := columnArg(where[name], column)
+		if err != nil {
+			t.Errorf("pgtestkit: invalid value for %s.%s: %v", table, name, err)
+			return assertRows{}, false
+		}
+		if arg == nil {
+			conditions = append(conditions, quoteIdentifier(name)+" IS NULL")
+			continue
+		}
+		args = append(args, arg)
+		// 타입이 다른 값도 비교할 수 있도록 컬럼 타입으로 변환한 뒤 텍스트로 비교
+		conditions = append(conditions, fmt.Sprintf("%s::text = CAST(CAST($%d AS text) AS %s)::text",
+			quoteIdentifier(name), len(args), column.typ))
+	}
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	if len(orderBy) > 0 {
+		query += " ORDER BY " + strings.Join(orderBy, ", ")
+	} else {
+		positions := make([]string, len(columns))
+		for i := range columns {
+			positions[i] = fmt.Sprint(i + 1)
+		}
+		query += " ORDER BY " + strings.Join(positions, ", ")
+	}
+
+	values, err := scanTextRows(context.Background(), db, query, args...)
+	if err != nil {
+		t.Errorf("pgtestkit: failed to read %s: %v", table, err)
+		return assertRows{}, false
+	}
+	return assertRows{columns: columns, values: values}, true
+}
+
+// normalizeRows 기대하는 행의 값을 컬럼 타입으로 변환한 뒤 PostgreSQL의 텍스트 표현으로 바꿉니다.
+func normalizeRows(ctx context.Context, db *sql.DB, info *tableInfo, columns []string, rows []map[string]interface{}) ([][]sql.NullString, error) {
+	result := make([][]sql.NullString, 0, len(rows))
+	for i, row := range rows {
+		for name := range row {
+			if !containsString(columns, name) {
+				return nil, fmt.Errorf("expected row %d has column %s that is not compared", i+1, name)
+			}
+		}
+
+		selects := make([]string, len(columns))
+		args := make([]interface{}, len(columns))
+		for j, name := range columns {
+			column, ok := info.columns[name]
+			if !ok {
+				return nil, fmt.Errorf("column %s does not exist in %s", name, info.table)
+			}
+			arg, err := columnArg(row[name], column)
+			if err != nil {
+				return nil, fmt.Errorf("expected row %d, column %s: %w", i+1, name, err)
+			}
+			args[j] = arg
+			selects[j] = fmt.Sprintf("CAST(CAST($%d AS text) AS %s)::text", j+1, column.typ)
+		}
+
+		values, err := scanTextRows(ctx, db, "SELECT "+strings.Join(selects, ", "), args...)
+		if err != nil {
+			return nil, fmt.Errorf("expected row %d: %w", i+1, err)
+		}
+		result = append(result, values[0])
+	}
+	return result, nil
+}
+
+// scanTextRows 텍스트로 변환된 컬럼을 NULL을 구분하여 읽습니다.
+func scanTextRows(ctx context.Context, db *sql.DB, query string, args ...interface{}) ([][]sql.NullString, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	var result [][]sql.NullString
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		result = append(result, values)
+	}
+	return result, rows.Err()
+}
+
+// sortRows 순서를 무시하고 비교하기 위해 행을 정렬합니다. NULL은 같은 값의 문자열보다 앞에 옵니다.
+func sortRows(rows [][]sql.NullString) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		for k := range rows[i] {
+			a, b := rows[i][k], rows[j][k]
+			if a == b {
+				continue
+			}
+			if a.String != b.String {
+				return a.String < b.String
+			}
+			return !a.Valid
+		}
+		return false
+	})
+}
+
+// renderTables 두 행 집합을 같은 컬럼 너비의 테이블로 출력합니다. 줄 단위로 비교할 수 있도록 너비를 맞춥니다.
+func renderTables(columns []string, a, b [][]sql.NullString) (string, string) {
+	widths := make([]int, len(columns))
+	for i, name := range columns {
+		widths[i] = utf8.RuneCountInString(name)
+	}
+	for _, rows := range [][][]sql.NullString{a, b} {
+		for _, row := range rows {
+			for i, v := range row {
+				widths[i] = max(widths[i], utf8.RuneCountInString(cellText(v)))
+			}
+		}
+	}
+
+	line := func(cells []string) string {
+		var sb strings.Builder
+		for i, cell := range cells {
+			if i > 0 {
+				sb.WriteString(" | ")
+			}
+			sb.WriteString(cell)
+			sb.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)))
+		}
+		return strings.TrimRight(sb.String(), " ") + "\n"
+	}
+
+	separators := make([]string, len(columns))
+	for i, w := range widths {
+		separators[i] = strings.Repeat("-", w)
+	}
+	header := line(columns) + strings.ReplaceAll(line(separators), " | ", "-+-")
+
+	render := func(rows [][]sql.NullString) string {
+		var sb strings.Builder
+		sb.WriteString(header)
+		for _, row := range rows {
+			cells := make([]string, len(row))
+			for i, v := range row {
+				cells[i] = cellText(v)
+			}
+			sb.WriteString(line(cells))
+		}
+		fmt.Fprintf(&sb, "(%d row(s))\n", len(rows))
+		return sb.String()
+	}
+	return render(a), render(b)
+}
+
+// cellText 테이블에 출력할 값입니다. NULL은 NULL로 표시합니다.
+func cellText(v sql.NullString) string {
+	if !v.Valid {
+		return "NULL"
+	}
+	return strings.ReplaceAll(v.String, "\n", `\n`)
+}
+
+// formatWhere 조건을 컬럼 이름순으로 출력합니다.
+func formatWhere(where map[string]interface{}) string {
+	names := make([]string, 0, len(where))
+	for name := range where {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		if where[name] == nil {
+			parts[i] = name + " IS NULL"
+		} else {
+			parts[i] = fmt.Sprintf("%s = %v", name, where[name])
+		}
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// containsString list에 s가 있는지 확인합니다.
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
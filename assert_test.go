@@ -0,0 +1,35 @@
+package pgtestkit
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestRenderTables(t *testing.T) {
+	str := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+	a := [][]sql.NullString{{str("1"), str("Alice")}}
+	b := [][]sql.NullString{{str("1"), {}}, {str("20"), str("Bob")}}
+
+	wantA := "id | name\n---+------\n1  | Alice\n(1 row(s))\n"
+	wantB := "id | name\n---+------\n1  | NULL\n20 | Bob\n(2 row(s))\n"
+	gotA, gotB := renderTables([]string{"id", "name"}, a, b)
+	if gotA != wantA {
+		t.Errorf("renderTables() first =\n%s\nwant\n%s", gotA, wantA)
+	}
+	if gotB != wantB {
+		t.Errorf("renderTables() second =\n%s\nwant\n%s", gotB, wantB)
+	}
+}
+
+func TestSortRows(t *testing.T) {
+	str := func(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
+	rows := [][]sql.NullString{{str("b")}, {str("")}, {{}}, {str("a")}}
+	sortRows(rows)
+
+	want := []sql.NullString{{}, str(""), str("a"), str("b")}
+	for i, row := range rows {
+		if row[0] != want[i] {
+			t.Errorf("Row %d = %+v, want %+v", i, row[0], want[i])
+		}
+	}
+}
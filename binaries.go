@@ -0,0 +1,207 @@
+package pgtestkit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+	"go.uber.org/zap"
+)
+
+const (
+	// OfflineCacheEnv 미리 준비된 바이너리 캐시 디렉토리를 지정하는 환경 변수입니다.
+	// WithOfflineCache와 같은 역할을 합니다.
+	OfflineCacheEnv = "PGTESTKIT_OFFLINE_CACHE"
+
+	// 바이너리 압축 해제가 끝났음을 표시하는 파일
+	binariesCompleteMarker = ".pgtestkit-complete"
+)
+
+// WithOfflineCache 네트워크 없이 사용할 수 있도록 미리 준비된 바이너리 캐시 디렉토리를 지정합니다.
+// 디렉토리에 압축 해제된 바이너리(bin/pg_ctl)가 있으면 그대로 사용하고,
+// 그렇지 않으면 embedded-postgres-binaries-*.txz 아카이브를 찾아 다운로드 없이 압축 해제합니다.
+// 이 옵션을 지정하지 않으면 PGTESTKIT_OFFLINE_CACHE 환경 변수의 값을 따릅니다.
+func WithOfflineCache(dir string) ServerOption {
+	return func(c *serverConfig) {
+		c.offlineCache = dir
+	}
+}
+
+// defaultCacheRoot 실행 간에 유지되는 pgtestkit 캐시의 루트 디렉토리 아래 경로를 반환합니다.
+func defaultCacheRoot(elem string) (string, error) {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "pgtestkit", elem), nil
+	}
+	userHome, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get user home directory: %w", err)
+	}
+	return filepath.Join(userHome, ".embedded-postgres-go", elem), nil
+}
+
+// binariesCacheKey 바이너리 캐시 디렉토리 이름을 생성합니다.
+// 아카이브를 알 수 있으면(오프라인 캐시) 아카이브 내용의 해시를 붙여, 내용이 다른 아카이브는 다른 디렉토리에
+// 압축 해제되도록 합니다. 따라서 다른 프로세스가 사용 중인 캐시를 지우거나 덮어쓸 일이 없습니다.
+// 다운로드하는 경우에는 버전별로 배포된 아카이브가 바뀌지 않으므로 버전, 운영체제, 아키텍처로 구분합니다.
+func binariesCacheKey(version embeddedpostgres.PostgresVersion, digest string) string {
+	key := fmt.Sprintf("%s-%s-%s", version, runtime.GOOS, runtime.GOARCH)
+	if digest != "" {
+		key += "-" + digest[:16]
+	}
+	return key
+}
+
+// binaryCache 서버 시작에 사용할 바이너리 위치를 나타냅니다.
+type binaryCache struct {
+	dir       string // 압축 해제된 바이너리 디렉토리 (BinariesPath)
+	cachePath string // 바이너리 아카이브 디렉토리 (CachePath, 비어 있으면 embeddedpostgres 기본값)
+	digest    string // 압축 해제에 사용되는 아카이브의 SHA-256 해시 (알 수 있는 경우)
+	unlock    func() // 압축 해제가 필요한 경우 보유 중인 파일 잠금
+}
+
+// release 보유 중인 잠금을 해제합니다.
+func (b *binaryCache) release() {
+	if b.unlock != nil {
+		b.unlock()
+		b.unlock = nil
+	}
+}
+
+// markComplete 압축 해제가 완료되었음을 기록하고 잠금을 해제합니다.
+func (b *binaryCache) markComplete() {
+	defer b.release()
+	if b.unlock == nil {
+		return
+	}
+
+	if err := os.WriteFile(filepath.Join(b.dir, binariesCompleteMarker), []byte(b.digest), 0o644); err != nil {
+		getLogger().Warn("Failed to write binaries cache marker", zap.String("path", b.dir), zap.Error(err))
+	}
+}
+
+// prepareBinaries 서버 설정에 맞는 바이너리 캐시를 준비합니다.
+// 캐시가 완전하지 않으면 파일 잠금을 획득한 채로 반환하므로, 호출자는 서버 시작 후
+// markComplete(성공) 또는 release(실패)를 반드시 호출해야 합니다.
+func prepareBinaries(ctx context.Context, cfg serverConfig) (*binaryCache, error) {
+	logger := getLogger()
+
+	offline := cfg.offlineCache
+	if offline == "" {
+		offline = os.Getenv(OfflineCacheEnv)
+	}
+
+	// 압축 해제된 바이너리가 준비된 오프라인 캐시는 그대로 사용
+	if offline != "" && fileExists(filepath.Join(offline, "bin", "pg_ctl")) {
+		logger.Info("Using pre-extracted offline PostgreSQL binaries", zap.String("path", offline))
+		return &binaryCache{dir: offline}, nil
+	}
+
+	root := cfg.binariesDir
+	if root == "" {
+		r, err := defaultCacheRoot("binaries")
+		if err != nil {
+			return nil, err
+		}
+		root = r
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create binaries cache directory: %w", err)
+	}
+
+	cache := &binaryCache{cachePath: offline}
+	if offline != "" {
+		archive, err := findArchive(offline, cfg.version)
+		if err != nil {
+			return nil, err
+		}
+		digest, err := fileDigest(archive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash offline archive %s: %w", archive, err)
+		}
+		cache.digest = digest
+	}
+	cache.dir = filepath.Join(root, binariesCacheKey(cfg.version, cache.digest))
+
+	if isBinariesComplete(cache.dir) {
+		logger.Debug("Using cached PostgreSQL binaries", zap.String("path", cache.dir))
+		return cache, nil
+	}
+
+	// 동시에 실행된 다른 go test 프로세스와 압축 해제가 겹치지 않도록 잠금
+	unlock, err := lockFile(ctx, cache.dir+".lock")
+	if err != nil {
+		return nil, err
+	}
+
+	// 잠금을 기다리는 동안 다른 프로세스가 압축 해제를 끝냈을 수 있음
+	if isBinariesComplete(cache.dir) {
+		unlock()
+		logger.Debug("PostgreSQL binaries were extracted by another process", zap.String("path", cache.dir))
+		return cache, nil
+	}
+
+	// 중간에 중단된 압축 해제의 흔적 제거
+	// (완료 표시가 없는 디렉토리는 잠금을 가진 프로세스만 사용하므로 지워도 안전함)
+	if err := os.RemoveAll(cache.dir); err != nil {
+		unlock()
+		return nil, fmt.Errorf("failed to clean up incomplete binaries cache %s: %w", cache.dir, err)
+	}
+
+	logger.Info("Extracting PostgreSQL binaries into shared cache", zap.String("path", cache.dir))
+	cache.unlock = unlock
+	return cache, nil
+}
+
+// isBinariesComplete 바이너리 캐시의 압축 해제가 끝났는지 확인합니다.
+// 캐시 디렉토리는 아카이브 내용으로 구분되므로 완료 표시만 확인합니다.
+func isBinariesComplete(dir string) bool {
+	return fileExists(filepath.Join(dir, binariesCompleteMarker))
+}
+
+// findArchive 오프라인 캐시에서 지정한 버전의 바이너리 아카이브를 찾습니다.
+func findArchive(dir string, version embeddedpostgres.PostgresVersion) (string, error) {
+	pattern := filepath.Join(dir, fmt.Sprintf("embedded-postgres-binaries-%s-*-%s.txz", runtime.GOOS, version))
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to search offline cache: %w", err)
+	}
+	for _, match := range matches {
+		// 아키텍처 이름은 embeddedpostgres가 정하므로 GOARCH가 포함된 것을 우선 사용
+		if strings.Contains(filepath.Base(match), runtime.GOARCH) {
+			return match, nil
+		}
+	}
+	if len(matches) > 0 {
+		return matches[0], nil
+	}
+	return "", fmt.Errorf("offline cache %s has no PostgreSQL %s binaries for %s/%s",
+		dir, version, runtime.GOOS, runtime.GOARCH)
+}
+
+// fileDigest 파일 내용의 SHA-256 해시를 반환합니다.
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// fileExists 파일이 존재하는지 확인합니다.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,105 @@
+package pgtestkit
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+)
+
+func TestPrepareBinariesSharedCache(t *testing.T) {
+	t.Setenv(OfflineCacheEnv, "")
+	cfg, err := newServerConfig([]ServerOption{WithBinariesCacheDir(t.TempDir())})
+	if err != nil {
+		t.Fatalf("Failed to create server config: %v", err)
+	}
+
+	// 첫 실행은 잠금을 획득한 채로 압축 해제를 맡음
+	first, err := prepareBinaries(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to prepare binaries: %v", err)
+	}
+	if first.unlock == nil {
+		t.Fatal("Expected the first run to hold the cache lock")
+	}
+	// 서버 시작 시 embeddedpostgres가 압축 해제하는 것을 흉내냄
+	if err := os.MkdirAll(filepath.Join(first.dir, "bin"), 0o755); err != nil {
+		t.Fatalf("Failed to create binaries dir: %v", err)
+	}
+	first.markComplete()
+
+	// 이후 실행은 잠금 없이 캐시를 재사용
+	second, err := prepareBinaries(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("Failed to prepare binaries: %v", err)
+	}
+	if second.unlock != nil {
+		t.Error("Expected a complete cache to be reused without locking")
+	}
+	if second.dir != first.dir {
+		t.Errorf("Expected cache dir %s, got %s", first.dir, second.dir)
+	}
+}
+
+func TestFindArchive(t *testing.T) {
+	dir := t.TempDir()
+	version := embeddedpostgres.V15
+	if _, err := findArchive(dir, version); err == nil {
+		t.Fatal("Expected an error for an empty offline cache")
+	}
+
+	name := "embedded-postgres-binaries-" + runtime.GOOS + "-" + runtime.GOARCH + "-" + string(version) + ".txz"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte("archive"), 0o644); err != nil {
+		t.Fatalf("Failed to write archive: %v", err)
+	}
+	archive, err := findArchive(dir, version)
+	if err != nil {
+		t.Fatalf("Failed to find archive: %v", err)
+	}
+	if filepath.Base(archive) != name {
+		t.Errorf("Expected archive %s, got %s", name, archive)
+	}
+}
+
+func TestPrepareBinariesOfflineArchive(t *testing.T) {
+	offline := t.TempDir()
+	cfg, err := newServerConfig([]ServerOption{WithBinariesCacheDir(t.TempDir()), WithOfflineCache(offline)})
+	if err != nil {
+		t.Fatalf("Failed to create server config: %v", err)
+	}
+	archive := filepath.Join(offline, "embedded-postgres-binaries-"+runtime.GOOS+"-"+runtime.GOARCH+"-"+string(cfg.version)+".txz")
+
+	prepare := func(content string) *binaryCache {
+		t.Helper()
+		if err := os.WriteFile(archive, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write archive: %v", err)
+		}
+		cache, err := prepareBinaries(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("Failed to prepare binaries: %v", err)
+		}
+		return cache
+	}
+
+	first := prepare("archive v1")
+	if err := os.MkdirAll(filepath.Join(first.dir, "bin"), 0o755); err != nil {
+		t.Fatalf("Failed to create binaries dir: %v", err)
+	}
+	first.markComplete()
+
+	// 내용이 바뀐 아카이브는 기존 캐시를 지우지 않고 다른 디렉토리에 압축 해제함
+	second := prepare("archive v2")
+	defer second.release()
+	if second.dir == first.dir {
+		t.Fatalf("Expected a different cache dir for a different archive, got %s", second.dir)
+	}
+	if second.unlock == nil {
+		t.Error("Expected the new archive to be extracted under the cache lock")
+	}
+	if !isBinariesComplete(first.dir) {
+		t.Error("Expected the cache of the previous archive to be left in place")
+	}
+}
@@ -0,0 +1,74 @@
+package pgtestkit
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ContextDBConnector 컨텍스트를 지원하는 DBConnector입니다.
+// 커넥터가 이 인터페이스를 구현하면 ...Context 함수들이 연결과 초기화에도 취소와 마감 시간을 전달합니다.
+// 구현하지 않은 커넥터는 재시도 사이에서만 컨텍스트를 확인합니다.
+type ContextDBConnector interface {
+	DBConnector
+
+	// ConnectContext 컨텍스트를 지원하는 Connect입니다.
+	ConnectContext(ctx context.Context, connString string) (interface{}, error)
+
+	// ResetContext 컨텍스트를 지원하는 Reset입니다.
+	ResetContext(ctx context.Context) error
+}
+
+// connectContext 커넥터가 지원하면 컨텍스트와 함께 연결합니다.
+func connectContext(ctx context.Context, connector DBConnector, connString string) (interface{}, error) {
+	if c, ok := connector.(ContextDBConnector); ok {
+		return c.ConnectContext(ctx, connString)
+	}
+	return connector.Connect(connString)
+}
+
+// resetContext 커넥터가 지원하면 컨텍스트와 함께 데이터베이스를 초기화합니다.
+func resetContext(ctx context.Context, connector DBConnector) error {
+	if c, ok := connector.(ContextDBConnector); ok {
+		return c.ResetContext(ctx)
+	}
+	return connector.Reset()
+}
+
+// sleepContext 지정한 시간만큼 대기하되, 컨텍스트가 취소되면 즉시 반환합니다.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// runContext 컨텍스트를 지원하지 않는 작업을 별도 고루틴에서 실행하고, 컨텍스트가 먼저 끝나면 즉시 반환합니다.
+// 이 경우 작업은 백그라운드에서 계속 실행되며, 완료 시 onAbandon이 작업 결과와 함께 호출됩니다.
+func runContext(ctx context.Context, op string, fn func() error, onAbandon func(err error)) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		if onAbandon != nil {
+			go func() {
+				onAbandon(<-done)
+			}()
+		}
+		return fmt.Errorf("%s: %w", op, ctx.Err())
+	}
+}
@@ -0,0 +1,93 @@
+package pgtestkit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 변경된 줄 주변에 함께 출력할 줄 수
+const diffContext = 2
+
+// diffLines 두 텍스트를 줄 단위로 비교하여 "-"(want에만 있음)와 "+"(got에만 있음)로 표시한 차이를 반환합니다.
+// 변경 주변의 몇 줄만 함께 출력하고, 같은 텍스트이면 빈 문자열을 반환합니다.
+func diffLines(want, got string) string {
+	if want == got {
+		return ""
+	}
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	// 공통 접두사와 접미사를 제외한 부분만 LCS로 비교
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type diffLine struct {
+		op   byte
+		text string
+	}
+	var lines []diffLine
+	for _, line := range a[:prefix] {
+		lines = append(lines, diffLine{' ', line})
+	}
+	i, j := 0, 0
+	for i < len(ma) || j < len(mb) {
+		switch {
+		case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+			lines = append(lines, diffLine{' ', ma[i]})
+			i++
+			j++
+		case i < len(ma) && (j == len(mb) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', ma[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', mb[j]})
+			j++
+		}
+	}
+	for _, line := range a[len(a)-suffix:] {
+		lines = append(lines, diffLine{' ', line})
+	}
+
+	// 변경된 줄과 그 주변만 출력
+	var out strings.Builder
+	last := -1
+	for k, line := range lines {
+		near := false
+		for d := max(0, k-diffContext); d <= min(len(lines)-1, k+diffContext); d++ {
+			if lines[d].op != ' ' {
+				near = true
+				break
+			}
+		}
+		if !near {
+			continue
+		}
+		if last >= 0 && k > last+1 {
+			out.WriteString("...\n")
+		}
+		fmt.Fprintf(&out, "%c %s\n", line.op, line.text)
+		last = k
+	}
+	return out.String()
+}
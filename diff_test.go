@@ -0,0 +1,16 @@
+package pgtestkit
+
+import "testing"
+
+func TestDiffLines(t *testing.T) {
+	if diff := diffLines("a\nb\n", "a\nb\n"); diff != "" {
+		t.Errorf("Expected no diff for equal text, got %q", diff)
+	}
+
+	want := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	got := "1\ntwo\n3\n4\n5\n6\n7\n8\n9\n10\n"
+	expected := "  1\n- 2\n+ two\n  3\n  4\n...\n  8\n  9\n+ 10\n"
+	if diff := diffLines(want, got); diff != expected {
+		t.Errorf("diffLines() =\n%s\nwant\n%s", diff, expected)
+	}
+}
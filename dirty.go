@@ -0,0 +1,220 @@
+package pgtestkit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// 변경된 테이블을 기록하는 테이블과 트리거 함수, 트리거 이름
+	dirtyTablesTable   = "pgtestkit_dirty_tables"
+	dirtyTableFunction = "pgtestkit_mark_dirty"
+	dirtyTableTrigger  = "pgtestkit_dirty"
+)
+
+// TruncateDirtyTables 마지막 초기화 이후 변경된 테이블만 TRUNCATE ... RESTART IDENTITY로 비우는 전략을 반환합니다.
+// 테이블이 많고 테스트가 그중 일부만 수정할 때 TruncateTables보다 훨씬 빠릅니다.
+//
+// 테스트 데이터베이스를 만들 때 대상 테이블마다 문장 단위 트리거를 설치하고,
+// INSERT, UPDATE, DELETE, TRUNCATE가 실행된 테이블을 첫 번째 대상 스키마의 pgtestkit_dirty_tables에 기록합니다.
+// 트리거가 없는 테이블(테스트 중에 만든 테이블 등)은 변경된 것으로 보고 비운 뒤 트리거를 설치합니다.
+// 변경된 테이블을 참조하는 대상 테이블도 함께 비워집니다. 정리한 테이블 수는 테스트에 연결된 DBClient이면
+// 테스트 로그(t.Logf, go test -v에서 보임)에, 그리고 SetLogging으로 켠 로그에 보고됩니다.
+// 트리거와 기록 테이블은 SchemaSnapshot 결과에도 나타납니다.
+func TruncateDirtyTables(opts ...ResetOption) ResetStrategy {
+	return &dirtyTableReset{cfg: newResetConfig(opts)}
+}
+
+// dirtyTableReset TruncateDirtyTables 전략입니다.
+type dirtyTableReset struct {
+	cfg resetConfig
+}
+
+// Reset 변경된 테이블을 비우고 기록을 지웁니다.
+func (r *dirtyTableReset) Reset(ctx context.Context, c *DBClient) error {
+	return r.sync(ctx, c, true)
+}
+
+// prepare 새로 만든 데이터베이스의 대상 테이블에 트리거를 설치합니다.
+func (r *dirtyTableReset) prepare(ctx context.Context, c *DBClient) error {
+	return r.sync(ctx, c, false)
+}
+
+// sync 변경 기록 테이블과 트리거를 준비하고, truncate가 true이면 변경된 테이블을 비웁니다.
+func (r *dirtyTableReset) sync(ctx context.Context, c *DBClient, truncate bool) error {
+	logger := c.logger()
+
+	var cleaned []resetTable
+	var tables []resetTable
+	err := withResetTx(ctx, c, func(tx *sql.Tx) error {
+		schema := r.cfg.schemasFor(c)[0]
+		if err := ensureDirtyTracking(ctx, tx, schema); err != nil {
+			return err
+		}
+		trackTable := quoteIdentifier(schema) + "." + quoteIdentifier(dirtyTablesTable)
+
+		var err error
+		tables, err = listResetTables(ctx, tx, r.cfg, c)
+		if err != nil {
+			return err
+		}
+		tracked, err := listTrackedTables(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		if truncate {
+			marked, err := queryStrings(ctx, tx, `
+				SELECT n.nspname, c.relname
+				FROM `+trackTable+` d
+				JOIN pg_class c ON c.oid = d.relid
+				JOIN pg_namespace n ON n.oid = c.relnamespace`)
+			if err != nil {
+				return fmt.Errorf("failed to read dirty tables: %w", err)
+			}
+			dirty := make(map[resetTable]bool, len(marked))
+			for _, row := range marked {
+				dirty[resetTable{row[0], row[1]}] = true
+			}
+			// 트리거가 없는 테이블은 변경 여부를 알 수 없으므로 비움
+			for _, table := range tables {
+				if !tracked[table] {
+					dirty[table] = true
+				}
+			}
+
+			refs, err := listForeignKeys(ctx, tx)
+			if err != nil {
+				return err
+			}
+			cleaned = dirtyClosure(tables, dirty, refs)
+			if len(cleaned) > 0 {
+				quoted := make([]string, len(cleaned))
+				for i, table := range cleaned {
+					quoted[i] = table.quoted()
+				}
+				if _, err := tx.ExecContext(ctx, "TRUNCATE TABLE "+strings.Join(quoted, ", ")+" RESTART IDENTITY"); err != nil {
+					return fmt.Errorf("failed to truncate dirty tables: %w", err)
+				}
+			}
+		}
+
+		// TRUNCATE가 남긴 기록까지 지움
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+trackTable); err != nil {
+			return fmt.Errorf("failed to clear dirty tables: %w", err)
+		}
+
+		installed := 0
+		for _, table := range tables {
+			if tracked[table] {
+				continue
+			}
+			if _, err := tx.ExecContext(ctx, fmt.Sprintf(
+				"CREATE TRIGGER %s AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON %s FOR EACH STATEMENT EXECUTE FUNCTION %s.%s()",
+				quoteIdentifier(dirtyTableTrigger), table.quoted(), quoteIdentifier(schema), quoteIdentifier(dirtyTableFunction))); err != nil {
+				return fmt.Errorf("failed to install dirty tracking trigger on %s: %w", table, err)
+			}
+			installed++
+		}
+		if installed > 0 {
+			logger.Debug("Installed dirty tracking triggers", zap.Int("tables", installed))
+		}
+		return nil
+	})
+	if err != nil || !truncate {
+		return err
+	}
+
+	// 트랜잭션이 커밋된 뒤에만 보고함
+	reportDirtyReset(c, logger, len(cleaned), len(tables))
+	return nil
+}
+
+// reportDirtyReset 정리한 테이블 수를 로그로 남기고, 테스트에 연결된 DBClient이면 테스트 로그에도 남깁니다.
+func reportDirtyReset(c *DBClient, logger *zap.Logger, cleaned, tables int) {
+	logger.Info("Reset dirty tables", zap.Int("cleaned", cleaned), zap.Int("tables", tables))
+	if c.tb != nil {
+		c.tb.Helper()
+		c.tb.Logf("pgtestkit: reset %d of %d tables in %s", cleaned, tables, c.DBName)
+	}
+}
+
+// ensureDirtyTracking schema에 변경 기록 테이블과 트리거 함수가 없으면 만듭니다.
+func ensureDirtyTracking(ctx context.Context, tx *sql.Tx, schema string) error {
+	table := quoteIdentifier(schema) + "." + quoteIdentifier(dirtyTablesTable)
+	function := quoteIdentifier(schema) + "." + quoteIdentifier(dirtyTableFunction)
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL AND to_regprocedure($2) IS NOT NULL",
+		table, function+"()").Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check dirty tracking: %w", err)
+	}
+	if exists {
+		return nil
+	}
+
+	// 기록은 초기화 사이에만 필요하므로 WAL을 남기지 않음
+	if _, err := tx.ExecContext(ctx, "CREATE UNLOGGED TABLE IF NOT EXISTS "+table+" (relid OID PRIMARY KEY)"); err != nil {
+		return fmt.Errorf("failed to create dirty tables table: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`CREATE OR REPLACE FUNCTION %s() RETURNS trigger LANGUAGE plpgsql AS $fn$
+BEGIN
+	INSERT INTO %s (relid) VALUES (TG_RELID) ON CONFLICT DO NOTHING;
+	RETURN NULL;
+END
+$fn$`, function, table)); err != nil {
+		return fmt.Errorf("failed to create dirty tracking function: %w", err)
+	}
+	return nil
+}
+
+// listTrackedTables 변경 기록 트리거가 설치된 테이블을 반환합니다.
+func listTrackedTables(ctx context.Context, tx *sql.Tx) (map[resetTable]bool, error) {
+	rows, err := queryStrings(ctx, tx, `
+		SELECT n.nspname, c.relname
+		FROM pg_trigger t
+		JOIN pg_class c ON c.oid = t.tgrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE t.tgname = $1`, dirtyTableTrigger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list dirty tracking triggers: %w", err)
+	}
+
+	tracked := make(map[resetTable]bool, len(rows))
+	for _, row := range rows {
+		tracked[resetTable{row[0], row[1]}] = true
+	}
+	return tracked, nil
+}
+
+// dirtyClosure tables 중 dirty이거나, 외래 키로 dirty인 테이블을 (간접적으로) 참조하는 테이블을 순서대로 반환합니다.
+// 참조하는 테이블을 함께 비우지 않으면 TRUNCATE가 실패하기 때문입니다.
+func dirtyClosure(tables []resetTable, dirty map[resetTable]bool, refs [][2]resetTable) []resetTable {
+	selected := make(map[resetTable]bool, len(tables))
+	for _, table := range tables {
+		selected[table] = true
+	}
+
+	for changed := true; changed; {
+		changed = false
+		for _, ref := range refs {
+			from, to := ref[0], ref[1]
+			if dirty[to] && selected[from] && !dirty[from] {
+				dirty[from] = true
+				changed = true
+			}
+		}
+	}
+
+	cleaned := make([]resetTable, 0, len(dirty))
+	for _, table := range tables {
+		if dirty[table] {
+			cleaned = append(cleaned, table)
+		}
+	}
+	return cleaned
+}
@@ -0,0 +1,55 @@
+package pgtestkit
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// logRecordingTB Logf로 남긴 메시지를 기록하는 testing.TB입니다.
+type logRecordingTB struct {
+	testing.TB
+	logs []string
+}
+
+func (l *logRecordingTB) Logf(format string, args ...any) {
+	l.logs = append(l.logs, fmt.Sprintf(format, args...))
+}
+
+func TestDirtyClosure(t *testing.T) {
+	authors := resetTable{"public", "authors"}
+	books := resetTable{"public", "books"}
+	reviews := resetTable{"public", "reviews"}
+	countries := resetTable{"public", "countries"}
+	archive := resetTable{"archive", "books"}
+
+	refs := [][2]resetTable{
+		{books, authors},
+		{reviews, books},
+		{archive, authors}, // 대상이 아닌 테이블은 추가하지 않음
+	}
+	dirty := map[resetTable]bool{authors: true}
+	got := dirtyClosure([]resetTable{authors, books, countries, reviews}, dirty, refs)
+	want := []resetTable{authors, books, reviews}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dirtyClosure() = %v, want %v", got, want)
+	}
+
+	if got := dirtyClosure([]resetTable{authors, books}, map[resetTable]bool{}, refs); len(got) != 0 {
+		t.Errorf("dirtyClosure() without dirty tables = %v, want none", got)
+	}
+}
+
+func TestReportDirtyReset(t *testing.T) {
+	tb := &logRecordingTB{TB: t}
+	reportDirtyReset(&DBClient{DBName: "test_db", tb: tb}, zap.NewNop(), 2, 5)
+	want := []string{"pgtestkit: reset 2 of 5 tables in test_db"}
+	if !reflect.DeepEqual(tb.logs, want) {
+		t.Errorf("Expected %v, got %v", want, tb.logs)
+	}
+
+	// 테스트가 연결되지 않은 DBClient는 로그로만 보고함
+	reportDirtyReset(&DBClient{DBName: "test_db"}, zap.NewNop(), 2, 5)
+}
@@ -42,5 +42,121 @@
 //	    // ... your test code ...
 //	}
 //
+// Server Configuration:
+//
+// The embedded server is configured with ServerOption values passed to StartServer
+// or RunTests. This is the supported configuration surface; every option is
+// validated and honoured by the connection strings handed to connectors.
+//
+//	func TestMain(m *testing.M) {
+//	    os.Exit(pgtestkit.RunTests(m,
+//	        pgtestkit.WithVersion(embeddedpostgres.V16),
+//	        pgtestkit.WithUser("app"),
+//	        pgtestkit.WithPassword("secret"),
+//	        pgtestkit.WithPostgresParam("max_connections", "200"),
+//	    ))
+//	}
+//
+// Available options are WithVersion, WithUser, WithPassword, WithLocale, WithPort,
+// WithDataDir, WithBinariesCacheDir, WithOfflineCache, WithStartTimeout, WithPostgresParam,
+// WithLogStatement, WithLogMinErrorStatement, WithSharedServer and WithDatabaseURL. Setting PGTESTKIT_DATABASE_URL runs the tests
+// against an existing PostgreSQL server instead of the embedded one.
+// StartEmbeddedPostgres and TestMainWrapper still accept a raw embeddedpostgres.Config
+// for backward compatibility, but pgtestkit overrides its port and paths. A server started
+// from such a config keeps its own start parameters, so server log routing (WithServerLogs),
+// Restart and the shared server mode are not available; use RunTests or StartServer for those.
+//
+// The server log is written to log/pgtestkit.log in the data directory, and each test
+// database created with New receives its own lines through t.Log (see WithServerLogs).
+//
+// Logging:
+//
+// The package logs nothing until SetLogging(true), SetLogger (a *slog.Logger) or
+// SetZapLogger is called, and SetLogging(false) silences every log call. Records
+// about a test database carry its database, schema and test name. Global loggers
+// are never replaced.
+//
+// Errors:
+//
+// ErrServerNotRunning, ErrServerStopped, ErrRestartUnsupported and ErrTemplateExists are sentinel errors, and *StartError
+// (phase and port) and *DatabaseError (database name and SQLSTATE) can be matched
+// with errors.As. DBClient.Close and StopPostgres join multiple failures with
+// errors.Join.
+//
+// Restarting the Server:
+//
+// NewServer returns a Server whose Start, Stop and Restart can be cycled within one
+// process. Restart keeps the port and data directory, so databases survive a real
+// PostgreSQL restart while open connections are dropped.
+//
+// Reset Strategies:
+//
+// WithResetStrategy replaces the connector's Reset with a built-in strategy:
+// TruncateTables, TruncateDirtyTables (only tables written since the last reset),
+// DeleteTables (in foreign-key order), RecreateSchemas (drop and re-migrate) or
+// RecreateDatabase (clone the template again). WithResetSchemas, WithResetTables
+// and WithResetExcludeTables choose what is reset. MigrationTables lists the
+// migration version tables every strategy skips, for connectors with their own Reset.
+//
+// Transaction Isolation:
+//
+// WithTxIsolation skips the database-per-test model: tests share one database cloned
+// from the template, and each test runs inside a transaction that is always rolled
+// back. BEGIN/COMMIT issued by application code become savepoints. Use TxSQLConnector
+// for database/sql, TxPgxConnector for pgx, or open TxDriverName in your own connector.
+//
+//	dbClient, helper := pgtestkit.New(t, &pgtestkit.TxSQLConnector{}, pgtestkit.WithTxIsolation())
+//	db := dbClient.Client.(*sql.DB)
+//
+// WithSchemaIsolation instead creates a unique schema per test in a shared database,
+// with search_path pinned to it, optionally cloned from a template schema created by
+// CreateTemplateSchema.
+//
+// Database Pool:
+//
+// EnableDBPool (or WithDBPool) keeps test databases created ahead of time so that
+// CreateTestDB does not wait for CREATE DATABASE, and drops returned databases in the
+// background. DBPoolStats reports hits and misses for tuning the pool size, and
+// DisableDBPool stops the pool and drops the databases it still holds.
+//
+// Migrations:
+//
+// Plain SQL migrations named NNN_name.up.sql and NNN_name.down.sql in an fs.FS are
+// applied by Migrate, by MigrationSetup (for CreateTemplateDB and CreateTemplateSchema)
+// or per test with WithMigrations. A failing statement is reported as a *MigrationError
+// with the file, statement, line and column. VerifyMigrations checks on a scratch
+// database that every down migration restores the previous schema.
+//
+// Fixtures:
+//
+// LoadFixtures inserts rows from YAML, JSON and CSV files named after their tables,
+// in foreign-key order. "$ref:table.label" values refer to the primary key of a
+// labeled row, and owned sequences are advanced past the loaded rows.
+//
+// Factories:
+//
+// DefineFactory registers defaults, sequences and associations for a table, and
+// Create/CreateMany insert rows, generating values for required columns and
+// creating parent rows for required foreign keys.
+//
+// Assertions:
+//
+// TestHelper.AssertRowCount, AssertRowExists, AssertNoRows and AssertTableEquals
+// check table contents and report mismatches through testing.TB, rendering the
+// actual rows as a table.
+//
+// Schema Snapshots:
+//
+// SchemaSnapshot renders the catalog as normalised, deterministic text, and
+// AssertSchemaGolden compares it with a golden file under testdata/. Set
+// PGTESTKIT_UPDATE_GOLDEN=1 to rewrite the golden files; go test -update works
+// when RunTests, TestMainWrapper or RegisterUpdateFlag defines the flag.
+//
+//	err := pgtestkit.CreateTemplateDB("golden", pgtestkit.MigrationSetup(migrations))
+//
+// Other tools plug in through the Migrator interface: WithMigratedTemplate migrates a
+// template when RunTests starts the server, and the golang-migrate, goose and Atlas
+// adapters live in separate modules under migrators/.
+//
 // For more examples and advanced usage, see the example directory.
 package pgtestkit
@@ -1,11 +1,16 @@
 package pgtestkit
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 	"syscall"
@@ -25,18 +30,26 @@ const (
 	DefaultDB       = "postgres"
 	DefaultLocale   = "en_US.UTF-8"
 	TestDBPrefix    = "testdb_"
+
+	// 이름 힌트의 최대 길이 (접두사와 고유 접미사를 포함해 63바이트 이내)
+	maxDBNameHintLength = 36
 )
 
 var (
 	// 임베디드 PostgreSQL 서버 관련 변수
-	serverOnce     sync.Once
-	server         *embeddedpostgres.EmbeddedPostgres
-	baseDBClient   *sql.DB
-	port           uint32
-	cacheDirectory string
-	serverStarted  bool
-	serverStopped  bool
-	serverMutex    sync.Mutex
+	signalOnce       sync.Once
+	server           *embeddedpostgres.EmbeddedPostgres
+	baseDBClient     *sql.DB
+	port             uint32
+	runtimeDirectory string
+	serverStarted    bool
+	serverStopped    bool
+	serverMutex      sync.Mutex
+
+	// 실행 중인 서버의 설정과 경로
+	activeConfig      = defaultServerConfig()
+	dataDirectory     string
+	binariesDirectory string
 )
 
 // DBConnector 데이터베이스 연결을 관리하는 인터페이스입니다.
@@ -55,84 +68,155 @@ type DBConnector interface {
 type DBClient struct {
 	Client           interface{} // 모든 종류의 DB 클라이언트를 저장
 	DBName           string
+	Schema           string // 스키마 격리 모드의 테스트 스키마 (WithSchemaIsolation)
 	ConnectionString string
 	connector        DBConnector
+
+	tb            testing.TB      // 데이터베이스를 소유한 테스트 (WithTB)
+	keepOnFailure bool            // 테스트 실패 시 데이터베이스 보존 여부
+	tx            *txSession      // 트랜잭션 격리 모드의 테스트 트랜잭션 (WithTxIsolation)
+	schemaDB      *sharedDatabase // 스키마 격리 모드의 공유 데이터베이스 (WithSchemaIsolation)
+
+	template      string        // 데이터베이스를 복제한 템플릿
+	migrator      Migrator      // 생성 시 적용한 마이그레이션 (WithMigrations, WithMigrator)
+	resetStrategy ResetStrategy // 커넥터의 Reset 대신 사용할 초기화 전략 (WithResetStrategy)
+	stopLogs      func()        // 서버 로그 전달 중지 (WithServerLogs)
 }
 
 // StartEmbeddedPostgres 임베디드 PostgreSQL 서버를 시작합니다.
-// 이 함수는 스레드 안전하며, 여러 번 호출되어도 서버는 한 번만 시작됩니다.
+// 이 함수는 스레드 안전하며, 서버가 실행 중이면 여러 번 호출되어도 다시 시작하지 않습니다.
+//
+// dbConfig의 포트와 경로 설정은 pgtestkit이 덮어쓰며, 사용자 이름과 비밀번호만 연결에 반영됩니다.
+// 사용자 이름이 비어 있는 등 dbConfig에서 계정을 읽을 수 없으면 에러를 반환합니다.
+// dbConfig를 지정하면 TestMainWrapper와 같은 제약이 있습니다.
+// 새 코드에서는 ServerOption을 받는 StartServer를 사용하세요.
 func StartEmbeddedPostgres(dbConfig *embeddedpostgres.Config) error {
-	var startErr error
-
-	serverOnce.Do(func() {
-		serverMutex.Lock()
-		defer serverMutex.Unlock()
-
-		logger := getLogger()
-		logger.Info("Starting embedded PostgreSQL server")
-
-		// 이미 서버가 중지된 경우
-		if serverStopped {
-			err := fmt.Errorf("server has been stopped and cannot be restarted")
-			logError("Failed to start server", err)
-			startErr = err
-			return
-		}
-
-		pg, p, err := startPostgresServer(dbConfig)
-		if err != nil {
-			logError("Failed to start PostgreSQL server", err)
-			startErr = fmt.Errorf("failed to start postgres server: %w", err)
-			return
-		}
-
-		server = pg
-		port = p
-		logger.Info("PostgreSQL server started", zap.Uint32("port", port))
+	return StartEmbeddedPostgresContext(context.Background(), dbConfig)
+}
 
-		// 기본 데이터베이스에 연결 (재시도 로직 포함)
-		db, err := connectToBaseDB(logger)
-		if err != nil {
-			if stopErr := pg.Stop(); stopErr != nil {
-				logError("Failed to stop PostgreSQL server after connection error", stopErr)
-			}
-			startErr = err
-			return
-		}
+// StartEmbeddedPostgresContext 컨텍스트를 지원하는 StartEmbeddedPostgres입니다.
+// 컨텍스트가 취소되거나 마감 시간이 지나면 서버 시작을 중단하고 에러를 반환합니다.
+func StartEmbeddedPostgresContext(ctx context.Context, dbConfig *embeddedpostgres.Config) error {
+	opts, err := withLegacyConfig(dbConfig)
+	if err != nil {
+		err = fmt.Errorf("invalid server configuration: %w", err)
+		logError("Invalid server configuration", err)
+		return err
+	}
+	return StartServerContext(ctx, opts...)
+}
 
-		baseDBClient = db
-		serverStarted = true
-		logger.Info("Successfully connected to PostgreSQL server")
+// startServer 검증된 설정으로 서버를 시작합니다. 이미 실행 중이면 아무것도 하지 않습니다.
+// 중지된 서버는 다시 시작할 수 있습니다.
+func startServer(ctx context.Context, cfg serverConfig) error {
+	serverMutex.Lock()
+	if serverStarted {
+		serverMutex.Unlock()
+		return nil
+	}
 
-		// PostgreSQL이 완전히 준비될 때까지 대기
-		if err := waitForPostgresToBeReady(db, logger); err != nil {
-			logError("PostgreSQL server is not fully ready", err)
-			if closeErr := db.Close(); closeErr != nil {
-				logError("Failed to close database connection", closeErr)
-			}
-			if stopErr := pg.Stop(); stopErr != nil {
-				logError("Failed to stop PostgreSQL server after readiness check error", stopErr)
-			}
-			startErr = fmt.Errorf("postgres server not ready: %w", err)
-			return
-		}
+	logger := getLogger()
+	logger.Info("Starting embedded PostgreSQL server")
+
+	activeConfig = cfg
+	preserveDataDirectory = false
+	var db *sql.DB
+	var err error
+	switch {
+	case cfg.external != nil:
+		// 외부 서버를 사용하는 경우 임베디드 서버를 시작하지 않음
+		db, err = attachExternalServer(ctx, cfg, logger)
+	case cfg.shared:
+		// 다른 go test 프로세스가 시작한 서버가 있으면 연결하고, 없으면 새로 시작하여 공유
+		db, err = attachSharedServer(ctx, cfg, logger)
+	default:
+		db, err = launchServer(ctx, cfg, logger)
+	}
+	if err != nil {
+		serverMutex.Unlock()
+		return err
+	}
 
-		logger.Info("PostgreSQL server is fully ready for connections")
+	baseDBClient = db
+	serverStarted = true
+	serverStopped = false
 
-		// SIGINT, SIGTERM 시그널 처리
+	// SIGINT, SIGTERM 시그널 처리 (서버를 다시 시작해도 한 번만 등록)
+	signalOnce.Do(func() {
 		c := make(chan os.Signal, 1)
 		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 		go func() {
 			sig := <-c
-			logger.Info("Received signal, shutting down", zap.String("signal", sig.String()))
+			getLogger().Info("Received signal, shutting down", zap.String("signal", sig.String()))
 			if err := StopPostgres(); err != nil {
 				logError("Error during shutdown", err)
 			}
 			os.Exit(0)
 		}()
 	})
+	serverMutex.Unlock()
+
+	// 요청된 경우 마이그레이션한 템플릿을 기본 템플릿으로 등록
+	if cfg.templateMigrator != nil {
+		setup := func(connString string) error {
+			return cfg.templateMigrator.Migrate(ctx, connString)
+		}
+		if err := CreateTemplateDBContext(ctx, cfg.templateName, setup); err != nil {
+			if stopErr := StopPostgresContext(context.WithoutCancel(ctx)); stopErr != nil {
+				logError("Failed to stop server after template migration error", stopErr)
+			}
+			return err
+		}
+	}
+
+	// 요청된 경우 테스트 데이터베이스를 미리 만들어 두는 풀 시작 (설정은 newServerConfig에서 검증됨)
+	// 풀이 마이그레이션된 템플릿으로 채워지도록 템플릿을 만든 뒤에 시작
+	if cfg.poolEnabled {
+		poolCfg, _ := newPoolConfig(cfg.pool)
+		serverMutex.Lock()
+		startDBPool(poolCfg)
+		serverMutex.Unlock()
+	}
+	return nil
+}
+
+// launchServer 이 프로세스가 소유하는 서버를 시작하고, 준비가 끝난 기본 데이터베이스 연결을 반환합니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func launchServer(ctx context.Context, cfg serverConfig, logger *zap.Logger) (*sql.DB, error) {
+	pg, p, err := startPostgresServer(ctx, cfg)
+	if err != nil {
+		logError("Failed to start PostgreSQL server", err)
+		return nil, err
+	}
 
-	return startErr
+	server = pg
+	port = p
+	logger.Info("PostgreSQL server started", zap.Uint32("port", port))
+
+	// 기본 데이터베이스에 연결 (재시도 로직 포함)
+	db, err := connectToBaseDB(ctx, logger)
+	if err != nil {
+		if stopErr := pg.Stop(); stopErr != nil {
+			logError("Failed to stop PostgreSQL server after connection error", stopErr)
+		}
+		return nil, &StartError{Phase: StartPhaseConnect, Port: port, Err: err}
+	}
+	logger.Info("Successfully connected to PostgreSQL server")
+
+	// PostgreSQL이 완전히 준비될 때까지 대기
+	if err := waitForPostgresToBeReady(ctx, db, logger); err != nil {
+		logError("PostgreSQL server is not fully ready", err)
+		if closeErr := db.Close(); closeErr != nil {
+			logError("Failed to close database connection", closeErr)
+		}
+		if stopErr := pg.Stop(); stopErr != nil {
+			logError("Failed to stop PostgreSQL server after readiness check error", stopErr)
+		}
+		return nil, &StartError{Phase: StartPhaseReady, Port: port, Err: err}
+	}
+
+	logger.Info("PostgreSQL server is fully ready for connections")
+	return db, nil
 }
 
 // Close 데이터베이스 연결을 안전하게 종료하고 테스트 데이터베이스를 삭제합니다.
@@ -153,13 +237,23 @@ func StartEmbeddedPostgres(dbConfig *embeddedpostgres.Config) error {
 //	    // ... 테스트 코드 ...
 //	}
 func (c *DBClient) Close() error {
+	return c.CloseContext(context.Background())
+}
+
+// CloseContext 컨텍스트를 지원하는 Close입니다.
+func (c *DBClient) CloseContext(ctx context.Context) error {
 	if c == nil {
 		return nil
 	}
 
-	logger := getLogger().With(zap.String("database", c.DBName))
+	logger := c.logger()
 	logger.Info("Closing database client and cleaning up resources")
 
+	// 테스트가 남긴 서버 로그를 모두 전달한 뒤 데이터베이스를 정리
+	if c.stopLogs != nil {
+		c.stopLogs()
+	}
+
 	var errs []error
 
 	// 커넥터를 통한 정리
@@ -174,11 +268,35 @@ func (c *DBClient) Close() error {
 		}
 	}
 
-	// 데이터베이스 삭제
-	if c.DBName != "" {
+	// 트랜잭션 격리 모드에서는 공유 데이터베이스를 삭제하지 않고 트랜잭션만 롤백
+	if c.tx != nil {
+		logger.Debug("Rolling back test transaction")
+		if err := c.tx.close(ctx); err != nil {
+			logger.Error("Failed to roll back test transaction", zap.Error(err))
+			errs = append(errs, err)
+		}
+	} else if c.DBName != "" && c.shouldKeep() {
+		// 실패한 테스트의 데이터베이스는 보존
+		serverMutex.Lock()
+		if c.schemaDB != nil {
+			c.schemaDB.kept = true
+		}
+		c.reportKeptDatabase()
+		serverMutex.Unlock()
+	} else if c.schemaDB != nil {
+		// 스키마 격리 모드에서는 공유 데이터베이스를 남기고 테스트 스키마만 삭제
+		logger.Debug("Dropping test schema", zap.String("schema", c.Schema))
+		if err := dropSchema(ctx, c.schemaDB.admin, c.Schema); err != nil {
+			logger.Error("Failed to drop test schema", zap.Error(err))
+			errs = append(errs, err)
+		} else {
+			logger.Info("Successfully dropped test schema", zap.String("schema", c.Schema))
+		}
+	} else if c.DBName != "" && c.recycle() {
+		logger.Debug("Handed test database to the pool for asynchronous drop")
+	} else if c.DBName != "" {
 		logger.Debug("Dropping test database", zap.String("database", c.DBName))
-		if err := dropDatabase(c.DBName); err != nil {
-			err = fmt.Errorf("failed to drop database %s: %w", c.DBName, err)
+		if err := dropDatabase(ctx, c.DBName); err != nil {
 			logger.Error("Failed to drop test database", zap.Error(err))
 			errs = append(errs, err)
 		} else {
@@ -187,7 +305,7 @@ func (c *DBClient) Close() error {
 	}
 
 	if len(errs) > 0 {
-		err := fmt.Errorf("%d error(s) occurred while closing DB client: %+v", len(errs), errs)
+		err := fmt.Errorf("%d error(s) occurred while closing DB client: %w", len(errs), errors.Join(errs...))
 		logger.Error("Errors occurred during database client close", zap.Errors("errors", errs))
 		return err
 	}
@@ -198,67 +316,110 @@ func (c *DBClient) Close() error {
 
 // getConnectionString 데이터베이스 연결 문자열을 생성합니다.
 func getConnectionString(dbName string) string {
-	return fmt.Sprintf("postgres://%s:%s@localhost:%d/%s?sslmode=disable&client_encoding=UTF8",
-		DefaultUser, DefaultPassword, port, dbName)
+	host, query := "localhost", defaultConnQuery
+	if ext := activeConfig.external; ext != nil {
+		host, query = ext.host, ext.query
+	}
+
+	user := url.User(activeConfig.user)
+	if activeConfig.password != "" {
+		user = url.UserPassword(activeConfig.user, activeConfig.password)
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     user,
+		Host:     net.JoinHostPort(host, strconv.FormatUint(uint64(port), 10)),
+		Path:     "/" + dbName,
+		RawQuery: query,
+	}
+	return u.String()
+}
+
+// baseDBName 데이터베이스 생성과 삭제에 사용하는 관리용 데이터베이스 이름을 반환합니다.
+func baseDBName() string {
+	if ext := activeConfig.external; ext != nil {
+		return ext.baseDB
+	}
+	return DefaultDB
 }
 
 // startPostgresServer PostgreSQL 서버를 시작합니다.
-func startPostgresServer(dbConfig *embeddedpostgres.Config) (*embeddedpostgres.EmbeddedPostgres, uint32, error) {
-	freePort, err := freeport.GetFreePort()
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get free port: %w", err)
+func startPostgresServer(ctx context.Context, cfg serverConfig) (*embeddedpostgres.EmbeddedPostgres, uint32, error) {
+	freePort := int(cfg.port)
+	if freePort == 0 {
+		p, err := freeport.GetFreePort()
+		if err != nil {
+			return nil, 0, &StartError{Phase: StartPhasePort, Err: fmt.Errorf("failed to get free port: %w", err)}
+		}
+		freePort = p
 	}
 
 	userHome, err := os.UserHomeDir()
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to get user home directory: %w", err)
+		return nil, 0, &StartError{Phase: StartPhaseRuntime, Port: uint32(freePort), Err: fmt.Errorf("failed to get user home directory: %w", err)}
 	}
 
-	cacheDirectory = filepath.Join(userHome, ".embedded-postgres-go", fmt.Sprintf(DefaultDB+"_%d", freePort))
+	runtimeDirectory = filepath.Join(userHome, ".embedded-postgres-go", fmt.Sprintf(DefaultDB+"_%d", freePort))
 
-	// 캐시 디렉토리 생성
-	if err := os.MkdirAll(cacheDirectory, 0o755); err != nil {
-		return nil, 0, fmt.Errorf("failed to create cache directory: %w", err)
+	// 런타임 디렉토리 생성 (실행마다 새로 만들고 서버 중지 시 삭제)
+	if err := os.MkdirAll(runtimeDirectory, 0o755); err != nil {
+		return nil, 0, &StartError{Phase: StartPhaseRuntime, Port: uint32(freePort), Err: fmt.Errorf("failed to create runtime directory: %w", err)}
 	}
 
-	var config embeddedpostgres.Config
-	if dbConfig == nil {
-		config = embeddedpostgres.DefaultConfig().
-			Username(DefaultUser).
-			Password(DefaultPassword).
-			Database(DefaultDB).
-			Version(embeddedpostgres.V15).
-			Port(uint32(freePort)).
-			RuntimePath(cacheDirectory).
-			DataPath(filepath.Join(cacheDirectory, "data")).
-			BinariesPath(cacheDirectory).
-			Locale(DefaultLocale)
-	} else {
-		config = *dbConfig
-		config = config.Port(uint32(freePort))
-		config = config.RuntimePath(cacheDirectory)
-		config = config.DataPath(filepath.Join(cacheDirectory, "data"))
-		config = config.BinariesPath(cacheDirectory)
+	dataDirectory = cfg.dataDir
+	if dataDirectory == "" {
+		dataDirectory = filepath.Join(runtimeDirectory, "data")
+	}
+
+	// 바이너리는 실행 간에 공유되는 캐시에 한 번만 압축 해제
+	// (embeddedpostgres.Config로 시작한 경우 버전을 알 수 없으므로 런타임 디렉토리 사용)
+	binaries := &binaryCache{dir: runtimeDirectory}
+	if cfg.legacy == nil {
+		binaries, err = prepareBinaries(ctx, cfg)
+		if err != nil {
+			return nil, 0, &StartError{Phase: StartPhaseBinaries, Port: uint32(freePort), Err: fmt.Errorf("failed to prepare postgres binaries: %w", err)}
+		}
 	}
+	binariesDirectory = binaries.dir
 
+	config := cfg.embeddedConfig(uint32(freePort), runtimeDirectory, dataDirectory, binariesDirectory)
+	if binaries.cachePath != "" {
+		config = config.CachePath(binaries.cachePath)
+	}
 	pg := embeddedpostgres.NewDatabase(config)
-	if err := pg.Start(); err != nil {
-		return nil, 0, fmt.Errorf("failed to start embedded postgres: %w", err)
+	err = runContext(ctx, "interrupted while starting postgres", pg.Start, func(err error) {
+		// 컨텍스트 취소 후 뒤늦게 시작된 서버는 즉시 중지
+		if err != nil {
+			binaries.release()
+			return
+		}
+		binaries.markComplete()
+		if stopErr := pg.Stop(); stopErr != nil {
+			logError("Failed to stop PostgreSQL server started after cancellation", stopErr)
+		}
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			binaries.release()
+		}
+		return nil, 0, &StartError{Phase: StartPhaseProcess, Port: uint32(freePort), Err: err}
 	}
+	binaries.markComplete()
 
 	return pg, uint32(freePort), nil
 }
 
 // connectToBaseDB 기본 데이터베이스에 연결합니다 (재시도 로직 포함).
-func connectToBaseDB(logger *zap.Logger) (*sql.DB, error) {
+func connectToBaseDB(ctx context.Context, logger *zap.Logger) (*sql.DB, error) {
 	maxRetries := 10
 	baseDelay := 100 * time.Millisecond
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		db, err := sql.Open("pgx", getConnectionString(DefaultDB))
+		db, err := sql.Open("pgx", getConnectionString(baseDBName()))
 		if err != nil {
 			if attempt == maxRetries {
-				return nil, fmt.Errorf("failed to open database connection after %d attempts: %w", maxRetries, err)
+				return nil, fmt.Errorf("failed to open database connection after %d attempts: %w", attempt, err)
 			}
 
 			delay := time.Duration(attempt) * baseDelay
@@ -266,15 +427,17 @@ func connectToBaseDB(logger *zap.Logger) (*sql.DB, error) {
 				zap.Int("attempt", attempt),
 				zap.Duration("delay", delay),
 				zap.Error(err))
-			time.Sleep(delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, fmt.Errorf("interrupted while connecting to base database: %w", err)
+			}
 			continue
 		}
 
 		// 연결 테스트
-		if err := db.Ping(); err != nil {
+		if err := db.PingContext(ctx); err != nil {
 			db.Close()
-			if attempt == maxRetries {
-				return nil, fmt.Errorf("failed to ping database after %d attempts: %w", maxRetries, err)
+			if attempt == maxRetries || ctx.Err() != nil {
+				return nil, fmt.Errorf("failed to ping database after %d attempts: %w", attempt, err)
 			}
 
 			delay := time.Duration(attempt) * baseDelay
@@ -282,7 +445,9 @@ func connectToBaseDB(logger *zap.Logger) (*sql.DB, error) {
 				zap.Int("attempt", attempt),
 				zap.Duration("delay", delay),
 				zap.Error(err))
-			time.Sleep(delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return nil, fmt.Errorf("interrupted while connecting to base database: %w", err)
+			}
 			continue
 		}
 
@@ -294,24 +459,24 @@ func connectToBaseDB(logger *zap.Logger) (*sql.DB, error) {
 }
 
 // waitForPostgresToBeReady PostgreSQL이 완전히 준비될 때까지 기다립니다.
-func waitForPostgresToBeReady(db *sql.DB, logger *zap.Logger) error {
+func waitForPostgresToBeReady(ctx context.Context, db *sql.DB, logger *zap.Logger) error {
 	maxRetries := 20
 	baseDelay := 50 * time.Millisecond
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		// bigserial 타입이 사용 가능한지 확인
 		var exists bool
-		err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM pg_type WHERE typname = 'bigserial')").Scan(&exists)
+		err := db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM pg_type WHERE typname = 'bigserial')").Scan(&exists)
 		if err == nil && exists {
 			logger.Debug("PostgreSQL bigserial type is available", zap.Int("attempt", attempt))
 			return nil
 		}
 
 		// 다른 기본 타입들도 확인
-		err = db.QueryRow("SELECT 1").Scan(&exists)
+		err = db.QueryRowContext(ctx, "SELECT 1").Scan(&exists)
 		if err != nil {
-			if attempt == maxRetries {
-				return fmt.Errorf("postgresql not ready after %d attempts: %w", maxRetries, err)
+			if attempt == maxRetries || ctx.Err() != nil {
+				return fmt.Errorf("postgresql not ready after %d attempts: %w", attempt, err)
 			}
 
 			delay := time.Duration(attempt) * baseDelay
@@ -319,7 +484,9 @@ func waitForPostgresToBeReady(db *sql.DB, logger *zap.Logger) error {
 				zap.Int("attempt", attempt),
 				zap.Duration("delay", delay),
 				zap.Error(err))
-			time.Sleep(delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return fmt.Errorf("interrupted while waiting for postgresql: %w", err)
+			}
 			continue
 		}
 
@@ -333,7 +500,9 @@ func waitForPostgresToBeReady(db *sql.DB, logger *zap.Logger) error {
 		logger.Debug("PostgreSQL basic check passed, checking bigserial availability",
 			zap.Int("attempt", attempt),
 			zap.Duration("delay", delay))
-		time.Sleep(delay)
+		if err := sleepContext(ctx, delay); err != nil {
+			return fmt.Errorf("interrupted while waiting for postgresql: %w", err)
+		}
 	}
 
 	return nil
@@ -341,7 +510,14 @@ func waitForPostgresToBeReady(db *sql.DB, logger *zap.Logger) error {
 
 // StopPostgres 임베디드 PostgreSQL 서버를 중지합니다.
 // 이 함수는 스레드 안전하며, 여러 번 호출되어도 안전합니다.
+// 중지한 뒤에는 StartServer나 Server.Start로 서버를 다시 시작할 수 있습니다.
 func StopPostgres() error {
+	return StopPostgresContext(context.Background())
+}
+
+// StopPostgresContext 컨텍스트를 지원하는 StopPostgres입니다.
+// 컨텍스트가 먼저 끝나면 서버 프로세스의 종료를 기다리지 않고 에러를 반환합니다.
+func StopPostgresContext(ctx context.Context) error {
 	serverMutex.Lock()
 	defer serverMutex.Unlock()
 
@@ -355,6 +531,28 @@ func StopPostgres() error {
 
 	var errs []error
 
+	// 데이터베이스 풀을 멈추고 남은 데이터베이스 삭제
+	if dbPool != nil {
+		errs = append(errs, dbPool.close(ctx)...)
+		dbPool = nil
+	}
+
+	// 트랜잭션/스키마 격리 모드의 공유 데이터베이스 삭제
+	if baseDBClient != nil {
+		errs = append(errs, dropSharedDatabases(ctx)...)
+	}
+
+	// 보존한 데이터베이스를 테스트가 직접 정리했다면 데이터 디렉토리도 보존하지 않음
+	if preserveDataDirectory && !keptDatabasesRemain(ctx) {
+		preserveDataDirectory = false
+	}
+	keptDatabases = nil
+
+	// 외부 서버에 남지 않도록 이 프로세스가 만든 템플릿 삭제
+	if activeConfig.external != nil && baseDBClient != nil {
+		errs = append(errs, dropCreatedTemplates(ctx)...)
+	}
+
 	// 기본 데이터베이스 연결 종료
 	if baseDBClient != nil {
 		logger.Debug("Closing base database connection")
@@ -367,39 +565,80 @@ func StopPostgres() error {
 		}
 	}
 
-	// 서버 중지
-	if server != nil {
-		logger.Debug("Stopping PostgreSQL server process")
-		if err := server.Stop(); err != nil {
-			err := fmt.Errorf("failed to stop embedded postgres: %w", err)
-			logger.Error("Failed to stop PostgreSQL server", zap.Error(err))
+	// 외부 서버는 중지하지 않고, 공유 서버는 마지막으로 사용을 마친 프로세스만 중지
+	shouldStop := activeConfig.external == nil
+	if !shouldStop {
+		logger.Info("Leaving external PostgreSQL server running")
+	}
+	if activeConfig.shared {
+		last, unlock, err := detachSharedServer(ctx, logger)
+		if err != nil {
+			logger.Error("Failed to detach from shared PostgreSQL server", zap.Error(err))
 			errs = append(errs, err)
+			shouldStop = false
 		} else {
-			serverStopped = true
-			logger.Info("Successfully stopped PostgreSQL server")
+			defer unlock()
+			shouldStop = last
 		}
 	}
 
-	serverStopped = true
-	serverStarted = false
+	if shouldStop {
+		// 서버 중지
+		if server != nil {
+			logger.Debug("Stopping PostgreSQL server process")
+			err := runContext(ctx, "interrupted while stopping postgres", server.Stop, func(err error) {
+				if err != nil {
+					logError("PostgreSQL server failed to stop after cancellation", err)
+				}
+			})
+			if err != nil {
+				err := fmt.Errorf("failed to stop embedded postgres: %w", err)
+				logger.Error("Failed to stop PostgreSQL server", zap.Error(err))
+				errs = append(errs, err)
+			} else {
+				logger.Info("Successfully stopped PostgreSQL server")
+			}
+		} else if activeConfig.shared {
+			// 다른 프로세스가 시작한 공유 서버는 pg_ctl로 중지
+			logger.Debug("Stopping shared PostgreSQL server started by another process")
+			if err := pgCtlStop(ctx, binariesDirectory, dataDirectory, "fast"); err != nil {
+				err := fmt.Errorf("failed to stop shared postgres: %w", err)
+				logger.Error("Failed to stop PostgreSQL server", zap.Error(err))
+				errs = append(errs, err)
+			} else {
+				logger.Info("Successfully stopped PostgreSQL server")
+			}
+		}
 
-	// 캐시 디렉토리 정리
-	if cacheDirectory != "" {
-		logger.Debug("Removing cache directory", zap.String("path", cacheDirectory))
-		if err := os.RemoveAll(cacheDirectory); err != nil {
-			err := fmt.Errorf("failed to remove cache directory %s: %w", cacheDirectory, err)
-			logger.Error("Failed to remove cache directory",
-				zap.String("path", cacheDirectory),
-				zap.Error(err))
-			errs = append(errs, err)
-		} else {
-			logger.Info("Successfully removed cache directory",
-				zap.String("path", cacheDirectory))
+		// 런타임 디렉토리 정리 (보존된 데이터베이스가 있으면 유지, 바이너리 캐시는 항상 유지)
+		if runtimeDirectory != "" && preserveDataDirectory {
+			logger.Warn("Keeping runtime directory because databases of failed tests were preserved",
+				zap.String("path", runtimeDirectory),
+				zap.String("restart", restartCommand()))
+		} else if runtimeDirectory != "" {
+			logger.Debug("Removing runtime directory", zap.String("path", runtimeDirectory))
+			if err := os.RemoveAll(runtimeDirectory); err != nil {
+				err := fmt.Errorf("failed to remove runtime directory %s: %w", runtimeDirectory, err)
+				logger.Error("Failed to remove runtime directory",
+					zap.String("path", runtimeDirectory),
+					zap.Error(err))
+				errs = append(errs, err)
+			} else {
+				logger.Info("Successfully removed runtime directory",
+					zap.String("path", runtimeDirectory))
+			}
 		}
 	}
 
+	// 서버와 함께 사라진 템플릿의 등록 해제 (다시 시작하면 WithMigratedTemplate이 다시 등록)
+	defaultTemplate = ""
+	defaultTemplateSchema = ""
+
+	serverStopped = true
+	serverStarted = false
+
 	if len(errs) > 0 {
-		err := fmt.Errorf("%d error(s) occurred while stopping PostgreSQL: %+v", len(errs), errs)
+		err := fmt.Errorf("%d error(s) occurred while stopping PostgreSQL: %w", len(errs), errors.Join(errs...))
 		logger.Error("Errors occurred while stopping PostgreSQL",
 			zap.Errors("errors", errs))
 		return err
@@ -417,18 +656,42 @@ func StopPostgres() error {
 //	func TestMain(m *testing.M) {
 //		os.Exit(testing.TestMainWrapper(m, nil))
 //	}
+//
+// dbConfig가 nil이 아니면 서버는 embeddedpostgres.Config의 시작 파라미터로 실행됩니다.
+// pgtestkit은 이 설정의 파라미터를 읽거나 덧붙일 수 없으므로 다음 기능은 동작하지 않습니다.
+//   - 서버 로그 수집: WithServerLogs가 테스트에 로그를 전달하지 않으며, log_statement 등은 dbConfig의 StartParameters로 지정해야 합니다.
+//   - 재시작: Server.Restart가 ErrRestartUnsupported를 반환합니다.
+//   - 서버 공유: WithSharedServer와 PGTESTKIT_SHARED_SERVER가 무시됩니다.
+//
+// 이 기능이 필요하면 ServerOption을 받는 RunTests를 사용하세요.
 func TestMainWrapper(m *testing.M, dbConfig *embeddedpostgres.Config) int {
+	opts, err := withLegacyConfig(dbConfig)
+	if err != nil {
+		logError("Invalid server configuration", err)
+		return 1
+	}
+	cfg, err := newServerConfig(opts)
+	if err != nil {
+		logError("Invalid server configuration", err)
+		return 1
+	}
+	return runTests(m, cfg)
+}
+
+// runTests 서버를 시작하고 테스트를 실행한 뒤 서버를 중지합니다.
+func runTests(m *testing.M, cfg serverConfig) int {
 	logger := getLogger()
 	logger.Info("Starting test execution")
 
 	// 서버 시작
 	logger.Info("Starting embedded PostgreSQL server for tests")
-	if err := StartEmbeddedPostgres(dbConfig); err != nil {
+	if err := startServer(context.Background(), cfg); err != nil {
 		logError("Failed to start embedded PostgreSQL server", err)
 		return 1
 	}
 
-	// 테스트 실행
+	// 테스트 실행 (플래그는 m.Run에서 파싱되므로 그 전에 -update 정의)
+	RegisterUpdateFlag()
 	logger.Info("Running tests...")
 	code := m.Run()
 
@@ -444,12 +707,16 @@ func TestMainWrapper(m *testing.M, dbConfig *embeddedpostgres.Config) int {
 }
 
 // createDatabase 새 데이터베이스를 생성합니다.
-func createDatabase(dbName string) error {
+// template이 비어 있지 않으면 해당 데이터베이스를 복제하여 생성합니다.
+func createDatabase(ctx context.Context, dbName, template string) error {
 	logger := getLogger().With(zap.String("database", dbName))
+	if template != "" {
+		logger = logger.With(zap.String("template", template))
+	}
 	logger.Debug("Creating database")
 
 	if baseDBClient == nil {
-		err := fmt.Errorf("base database client is not initialized")
+		err := fmt.Errorf("base database client is not initialized: %w", ErrServerNotRunning)
 		logError("Cannot create database: base connection not available", err)
 		return err
 	}
@@ -460,18 +727,20 @@ func createDatabase(dbName string) error {
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		// 이미 존재하는 데이터베이스인지 확인
 		var exists bool
-		err := baseDBClient.QueryRow(
+		err := baseDBClient.QueryRowContext(ctx,
 			"SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", dbName).Scan(&exists)
 		if err != nil {
-			if attempt == maxRetries {
-				return fmt.Errorf("failed to check if database exists after %d attempts: %w", maxRetries, err)
+			if attempt == maxRetries || ctx.Err() != nil {
+				return newDatabaseError("create", dbName, fmt.Errorf("failed to check if database exists after %d attempts: %w", attempt, err))
 			}
 			delay := time.Duration(attempt) * baseDelay
 			logger.Debug("Database existence check failed, retrying",
 				zap.Int("attempt", attempt),
 				zap.Duration("delay", delay),
 				zap.Error(err))
-			time.Sleep(delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return fmt.Errorf("interrupted while creating database %s: %w", dbName, err)
+			}
 			continue
 		}
 
@@ -483,18 +752,30 @@ func createDatabase(dbName string) error {
 		// 데이터베이스 생성
 		logger.Info("Creating new database", zap.Int("attempt", attempt))
 		// 안전하게 식별자를 이스케이프
-		escapedDBName := `"` + strings.ReplaceAll(dbName, `"`, `""`) + `"`
-		createQuery := fmt.Sprintf("CREATE DATABASE %s", escapedDBName)
-		_, err = baseDBClient.Exec(createQuery)
+		createQuery := fmt.Sprintf("CREATE DATABASE %s", quoteIdentifier(dbName))
+		if template != "" {
+			createQuery += fmt.Sprintf(" TEMPLATE %s", quoteIdentifier(template))
+		}
+		_, err = baseDBClient.ExecContext(ctx, createQuery)
 		if err != nil {
-			// 데이터베이스가 이미 존재한다는 에러인 경우 성공으로 처리
-			if strings.Contains(err.Error(), "already exists") {
+			// 다른 프로세스가 먼저 만든 경우 성공으로 처리
+			if code := SQLState(err); code == sqlStateDuplicateDatabase || code == sqlStateUniqueViolation {
 				logger.Debug("Database was created by another process, continuing")
 				return nil
 			}
 
-			if attempt == maxRetries {
-				return fmt.Errorf("failed to create database %s after %d attempts: %w", dbName, maxRetries, err)
+			// 템플릿에 남아 있는 연결 때문에 복제가 실패한 경우 연결을 정리하고 재시도
+			if isTemplateInUseError(err) {
+				logger.Warn("Template database is being accessed by other users, terminating connections",
+					zap.Int("attempt", attempt))
+				if termErr := terminateConnections(ctx, template); termErr != nil {
+					logger.Warn("Failed to terminate template connections", zap.Error(termErr))
+				}
+			}
+
+			if attempt == maxRetries || ctx.Err() != nil {
+				logger.Debug("Giving up creating database", zap.Int("attempts", attempt))
+				return newDatabaseError("create", dbName, err)
 			}
 
 			delay := time.Duration(attempt) * baseDelay
@@ -502,7 +783,9 @@ func createDatabase(dbName string) error {
 				zap.Int("attempt", attempt),
 				zap.Duration("delay", delay),
 				zap.Error(err))
-			time.Sleep(delay)
+			if err := sleepContext(ctx, delay); err != nil {
+				return fmt.Errorf("interrupted while creating database %s: %w", dbName, err)
+			}
 			continue
 		}
 
@@ -515,7 +798,7 @@ func createDatabase(dbName string) error {
 
 // dropDatabase는 데이터베이스를 삭제합니다.
 // 이 함수는 내부적으로 사용되며, 외부에서는 DBClient.Close()를 통해 호출되어야 합니다.
-func dropDatabase(dbName string) error {
+func dropDatabase(ctx context.Context, dbName string) error {
 	if dbName == "" {
 		return fmt.Errorf("database name cannot be empty")
 	}
@@ -524,18 +807,18 @@ func dropDatabase(dbName string) error {
 	logger.Info("Starting database drop process")
 
 	if baseDBClient == nil {
-		err := fmt.Errorf("base database client is not initialized")
+		err := fmt.Errorf("base database client is not initialized: %w", ErrServerNotRunning)
 		logger.Error("Cannot drop database: base client is nil", zap.Error(err))
 		return err
 	}
 
 	// 데이터베이스 존재 여부 확인
 	var exists bool
-	err := baseDBClient.QueryRow(
+	err := baseDBClient.QueryRowContext(ctx,
 		`SELECT 1 FROM pg_database WHERE datname = $1`, dbName).Scan(&exists)
 
 	if err != nil && err != sql.ErrNoRows {
-		err = fmt.Errorf("failed to check if database exists: %w", err)
+		err := newDatabaseError("drop", dbName, fmt.Errorf("failed to check if database exists: %w", err))
 		logger.Error("Database existence check failed", zap.Error(err))
 		return err
 	}
@@ -547,13 +830,7 @@ func dropDatabase(dbName string) error {
 
 	// 다른 세션이 연결되어 있는 경우 강제 종료
 	logger.Debug("Terminating active connections to database")
-	_, err = baseDBClient.Exec(
-		`SELECT pg_terminate_backend(pid) 
-		 FROM pg_stat_activity 
-		 WHERE datname = $1 
-		 AND pid <> pg_backend_pid()`, dbName)
-
-	if err != nil {
+	if err := terminateConnections(ctx, dbName); err != nil {
 		logger.Warn("Failed to terminate some database connections",
 			zap.Error(err),
 			zap.String("database", dbName))
@@ -563,12 +840,11 @@ func dropDatabase(dbName string) error {
 	// 데이터베이스 삭제
 	logger.Info("Dropping database")
 	// 안전하게 식별자를 이스케이프
-	escapedDBName := `"` + strings.ReplaceAll(dbName, `"`, `""`) + `"`
-	dropQuery := fmt.Sprintf("DROP DATABASE IF EXISTS %s", escapedDBName)
-	_, err = baseDBClient.Exec(dropQuery)
+	dropQuery := fmt.Sprintf("DROP DATABASE IF EXISTS %s", quoteIdentifier(dbName))
+	_, err = baseDBClient.ExecContext(ctx, dropQuery)
 
 	if err != nil {
-		err = fmt.Errorf("failed to drop database %s: %w", dbName, err)
+		err := newDatabaseError("drop", dbName, err)
 		logger.Error("Database drop failed",
 			zap.String("database", dbName),
 			zap.Error(err))
@@ -580,15 +856,49 @@ func dropDatabase(dbName string) error {
 }
 
 // generateTestDBName 테스트용 데이터베이스 이름을 생성합니다.
-func generateTestDBName() string {
+// hint가 주어지면 디버깅이 쉽도록 이름에 포함합니다.
+func generateTestDBName(hint string) string {
+	if hint = sanitizeDBNameHint(hint); hint != "" {
+		return fmt.Sprintf("%s%s_%s_%s", TestDBPrefix, hint,
+			strconv.FormatInt(int64(os.Getpid()), 36), strconv.FormatInt(time.Now().UnixNano(), 36))
+	}
+
 	// 더 높은 유니크성을 위해 랜덤 요소 추가
 	return fmt.Sprintf("%s%d_%d_%d", TestDBPrefix, os.Getpid(), time.Now().UnixNano(),
 		(time.Now().UnixNano() % 1000000))
 }
 
+// sanitizeDBNameHint 데이터베이스 이름에 사용할 수 있도록 힌트를 소문자, 숫자, 밑줄로 변환합니다.
+// PostgreSQL 식별자 길이 제한(63바이트)을 넘지 않도록 maxDBNameHintLength로 자릅니다.
+func sanitizeDBNameHint(hint string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(hint) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteByte('_')
+			underscore = true
+		}
+		if b.Len() >= maxDBNameHintLength {
+			break
+		}
+	}
+	return strings.TrimRight(b.String(), "_")
+}
+
 // CreateTestDB 지정된 커넥터를 사용하여 테스트 데이터베이스를 생성합니다.
 // 사용자는 반드시 DBConnector 인터페이스를 구현한 커넥터를 전달해야 합니다.
-func CreateTestDB(connector DBConnector) (*DBClient, error) {
+// 기본 템플릿이 등록되어 있거나 WithTemplate 옵션이 주어지면 템플릿을 복제하여 생성합니다.
+func CreateTestDB(connector DBConnector, opts ...Option) (*DBClient, error) {
+	return CreateTestDBContext(context.Background(), connector, opts...)
+}
+
+// CreateTestDBContext 컨텍스트를 지원하는 CreateTestDB입니다.
+// 데이터베이스 생성, 연결, 초기화의 모든 단계에서 컨텍스트의 취소와 마감 시간을 따릅니다.
+// serverMutex는 데이터베이스를 만드는 동안에만 보유하므로 마이그레이션, 연결, 초기화는 병렬로 실행됩니다.
+func CreateTestDBContext(ctx context.Context, connector DBConnector, opts ...Option) (*DBClient, error) {
 	logger := getLogger()
 	logger.Info("Creating test database")
 
@@ -599,76 +909,129 @@ func CreateTestDB(connector DBConnector) (*DBClient, error) {
 	}
 
 	serverMutex.Lock()
-	defer serverMutex.Unlock()
-
 	if !serverStarted || serverStopped {
-		err := fmt.Errorf("database server is not running")
+		err := serverNotRunningError()
+		serverMutex.Unlock()
 		logError("Cannot create test database", err)
 		return nil, err
 	}
 
-	dbName := generateTestDBName()
+	options := newDBOptions(opts)
+	if options.tb != nil {
+		logger = logger.With(zap.String("test", options.tb.Name()))
+	}
+	if options.schemaIsolation {
+		// 스키마 생성은 서버 잠금 없이 병렬로 수행
+		serverMutex.Unlock()
+		return createSchemaTestDB(ctx, connector, options, logger)
+	}
+
+	if options.txIsolation {
+		serverMutex.Unlock()
+		if options.migrator != nil {
+			err := fmt.Errorf("WithMigrations cannot be used with WithTxIsolation, apply migrations to a template with MigrationSetup instead")
+			logError("Invalid test database options", err)
+			return nil, err
+		}
+		if options.resetStrategy != nil {
+			err := fmt.Errorf("WithResetStrategy cannot be used with WithTxIsolation, reset always rolls back the test transaction")
+			logError("Invalid test database options", err)
+			return nil, err
+		}
+		return createTxTestDB(ctx, connector, options, logger)
+	}
+
+	dbName := generateTestDBName(options.nameHint)
 	logger = logger.With(zap.String("database", dbName))
 	logger.Debug("Generated test database name")
 
-	// 데이터베이스 생성
-	logger.Info("Creating test database")
-	if err := createDatabase(dbName); err != nil {
-		err = fmt.Errorf("failed to create test database: %w", err)
-		logError("Failed to create test database", err)
-		return nil, err
+	// 풀에 미리 만들어 둔 데이터베이스가 있으면 사용하고, 없으면 생성
+	if pooled, ok := takePooledDB(ctx, options, dbName, logger); ok {
+		dbName = pooled
+		logger = logger.With(zap.String("database", dbName))
+	} else {
+		logger.Info("Creating test database", zap.String("template", options.template))
+		if err := createDatabase(ctx, dbName, options.template); err != nil {
+			serverMutex.Unlock()
+			err = fmt.Errorf("failed to create test database: %w", err)
+			logError("Failed to create test database", err)
+			return nil, err
+		}
 	}
 
-	// 데이터베이스 연결 문자열 생성
+	// 데이터베이스 연결 문자열 생성 (이후 단계는 서버 잠금 없이 수행)
 	connString := getConnectionString(dbName)
+	logPath := serverLogPath()
+	serverMutex.Unlock()
+
+	// 요청된 경우 커넥터가 연결하기 전에 마이그레이션 적용
+	if options.migrator != nil {
+		logger.Debug("Applying migrations to test database")
+		if err := options.migrator.Migrate(ctx, connString); err != nil {
+			logError("Failed to apply migrations, cleaning up", err)
+			if dropErr := dropDatabase(context.WithoutCancel(ctx), dbName); dropErr != nil {
+				logError("Failed to clean up test database after migration error", dropErr)
+			}
+			return nil, fmt.Errorf("failed to migrate test database: %w", err)
+		}
+	}
+
 	logger.Debug("Connecting to test database")
 
 	// 커넥터를 사용하여 데이터베이스에 연결 (재시도 로직 포함)
-	client, err := connectWithRetry(connector, connString, logger)
+	client, err := connectWithRetry(ctx, connector, connString, logger)
 	if err != nil {
 		// 생성된 데이터베이스 정리
 		logger.Error("Failed to connect to test database, cleaning up", zap.Error(err))
-		if dropErr := dropDatabase(dbName); dropErr != nil {
+		if dropErr := dropDatabase(context.WithoutCancel(ctx), dbName); dropErr != nil {
 			logError("Failed to clean up test database after connection error", dropErr)
 		}
-		return nil, fmt.Errorf("failed to connect to test database: %w", err)
+		return nil, newDatabaseError("connect", dbName, err)
+	}
+
+	dbClient := &DBClient{
+		Client:           client,
+		DBName:           dbName,
+		ConnectionString: connString,
+		connector:        connector,
+		tb:               options.tb,
+		keepOnFailure:    *options.keepOnFailure,
+		template:         options.template,
+		migrator:         options.migrator,
+		resetStrategy:    options.resetStrategy,
 	}
 
 	// Reset 호출로 데이터베이스 초기화 (재시도 로직 포함)
 	logger.Debug("Resetting test database")
-	if err := resetWithRetry(connector, logger); err != nil {
+	if err := initialReset(ctx, dbClient, logger); err != nil {
 		logger.Error("Failed to reset test database, cleaning up", zap.Error(err))
 		if closeErr := connector.Close(); closeErr != nil {
 			logError("Failed to close connector after reset error", closeErr)
 		}
-		if dropErr := dropDatabase(dbName); dropErr != nil {
+		if dropErr := dropDatabase(context.WithoutCancel(ctx), dbName); dropErr != nil {
 			logError("Failed to clean up test database after reset error", dropErr)
 		}
 		return nil, fmt.Errorf("failed to reset test database: %w", err)
 	}
+	dbClient.routeServerLogs(options, logPath)
 
 	logger.Info("Successfully created and initialized test database")
-	return &DBClient{
-		Client:           client,
-		DBName:           dbName,
-		ConnectionString: connString,
-		connector:        connector,
-	}, nil
+	return dbClient, nil
 }
 
 // connectWithRetry 커넥터 연결을 재시도합니다.
-func connectWithRetry(connector DBConnector, connString string, logger *zap.Logger) (interface{}, error) {
+func connectWithRetry(ctx context.Context, connector DBConnector, connString string, logger *zap.Logger) (interface{}, error) {
 	maxRetries := 5
 	baseDelay := 50 * time.Millisecond
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		client, err := connector.Connect(connString)
+		client, err := connectContext(ctx, connector, connString)
 		if err == nil {
 			return client, nil
 		}
 
-		if attempt == maxRetries {
-			return nil, fmt.Errorf("failed to connect after %d attempts: %w", maxRetries, err)
+		if attempt == maxRetries || ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to connect after %d attempts: %w", attempt, err)
 		}
 
 		delay := time.Duration(attempt) * baseDelay
@@ -678,25 +1041,27 @@ func connectWithRetry(connector DBConnector, connString string, logger *zap.Logg
 			zap.Duration("delay", delay),
 			zap.Error(err))
 
-		time.Sleep(delay)
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, fmt.Errorf("interrupted while connecting: %w", err)
+		}
 	}
 
 	return nil, fmt.Errorf("unexpected error in connect retry loop")
 }
 
 // resetWithRetry 데이터베이스 리셋을 재시도합니다.
-func resetWithRetry(connector DBConnector, logger *zap.Logger) error {
+func resetWithRetry(ctx context.Context, connector DBConnector, logger *zap.Logger) error {
 	maxRetries := 3
 	baseDelay := 50 * time.Millisecond
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		err := connector.Reset()
+		err := resetContext(ctx, connector)
 		if err == nil {
 			return nil
 		}
 
-		if attempt == maxRetries {
-			return fmt.Errorf("failed to reset after %d attempts: %w", maxRetries, err)
+		if attempt == maxRetries || ctx.Err() != nil {
+			return fmt.Errorf("failed to reset after %d attempts: %w", attempt, err)
 		}
 
 		delay := time.Duration(attempt) * baseDelay
@@ -706,7 +1071,9 @@ func resetWithRetry(connector DBConnector, logger *zap.Logger) error {
 			zap.Duration("delay", delay),
 			zap.Error(err))
 
-		time.Sleep(delay)
+		if err := sleepContext(ctx, delay); err != nil {
+			return fmt.Errorf("interrupted while resetting: %w", err)
+		}
 	}
 
 	return fmt.Errorf("unexpected error in reset retry loop")
@@ -715,6 +1082,9 @@ func resetWithRetry(connector DBConnector, logger *zap.Logger) error {
 // TestHelper 테스트에 유용한 헬퍼 함수들을 제공합니다.
 type TestHelper struct {
 	dbClient *DBClient
+
+	mu sync.Mutex
+	db *sql.DB // Assert* 메서드가 사용하는 연결 (처음 사용할 때 열림)
 }
 
 // NewTestHelper 새로운 TestHelper 인스턴스를 생성합니다.
@@ -726,7 +1096,12 @@ func NewTestHelper(dbClient *DBClient) *TestHelper {
 
 // ResetDB 데이터베이스를 초기 상태로 되돌립니다.
 func (h *TestHelper) ResetDB() error {
-	logger := getLogger().With(zap.String("database", h.dbClient.DBName))
+	return h.ResetDBContext(context.Background())
+}
+
+// ResetDBContext 컨텍스트를 지원하는 ResetDB입니다.
+func (h *TestHelper) ResetDBContext(ctx context.Context) error {
+	logger := h.dbClient.logger()
 	logger.Debug("Resetting database to initial state")
 
 	if h.dbClient.connector == nil {
@@ -735,7 +1110,16 @@ func (h *TestHelper) ResetDB() error {
 		return err
 	}
 
-	if err := h.dbClient.connector.Reset(); err != nil {
+	if strategy := h.dbClient.resetStrategy; strategy != nil {
+		if err := strategy.Reset(ctx, h.dbClient); err != nil {
+			logError("Failed to reset database with reset strategy", err)
+			return fmt.Errorf("failed to reset database: %w", err)
+		}
+		logger.Info("Successfully reset database with reset strategy")
+		return nil
+	}
+
+	if err := resetContext(ctx, h.dbClient.connector); err != nil {
 		logError("Failed to reset database", err)
 		return fmt.Errorf("failed to reset database: %w", err)
 	}
@@ -747,7 +1131,7 @@ func (h *TestHelper) ResetDB() error {
 // MustResetDB 데이터베이스를 초기 상태로 되돌리고, 실패하면 테스트를 즉시 중단합니다.
 func (h *TestHelper) MustResetDB(t testing.TB) {
 	t.Helper()
-	logger := getLogger().With(zap.String("database", h.dbClient.DBName))
+	logger := h.dbClient.logger()
 	logger.Debug("Resetting database (must)")
 
 	if err := h.ResetDB(); err != nil {
@@ -756,14 +1140,29 @@ func (h *TestHelper) MustResetDB(t testing.TB) {
 	}
 }
 
+// closeAssertDB Assert* 메서드가 연 연결을 닫습니다. 커넥터는 닫지 않습니다.
+func (h *TestHelper) closeAssertDB() {
+	h.mu.Lock()
+	db := h.db
+	h.db = nil
+	h.mu.Unlock()
+	if db != nil {
+		if err := db.Close(); err != nil {
+			logError("Failed to close assertion connection", err)
+		}
+	}
+}
+
 // Close 테스트 헬퍼에서 사용한 리소스를 정리합니다.
 func (h *TestHelper) Close() error {
 	logger := getLogger()
 	if h.dbClient != nil {
-		logger = logger.With(zap.String("database", h.dbClient.DBName))
+		logger = h.dbClient.logger()
 	}
 	logger.Debug("Closing test helper")
 
+	h.closeAssertDB()
+
 	if h.dbClient.connector != nil {
 		logger.Debug("Closing database connector")
 		if err := h.dbClient.connector.Close(); err != nil {
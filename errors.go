@@ -0,0 +1,118 @@
+package pgtestkit
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL SQLSTATE 코드
+const (
+	sqlStateUniqueViolation   = "23505" // pg_database의 이름 인덱스에서 동시 생성이 충돌한 경우
+	sqlStateDuplicateDatabase = "42P04"
+	sqlStateObjectInUse       = "55006" // 템플릿이나 데이터베이스에 다른 세션이 연결된 경우
+)
+
+var (
+	// ErrServerNotRunning 서버가 시작되지 않았거나 이미 중지된 상태에서 데이터베이스를 요청하면 반환됩니다.
+	ErrServerNotRunning = errors.New("database server is not running")
+
+	// ErrServerStopped 중지된 서버에 데이터베이스를 요청하면 ErrServerNotRunning과 함께 반환됩니다.
+	// StartServer나 Server.Start로 서버를 다시 시작할 수 있습니다.
+	ErrServerStopped = errors.New("server was stopped")
+
+	// ErrTemplateExists CreateTemplateDB에 전달한 이름의 데이터베이스가 이미 있지만 이 프로세스가 만든 템플릿이 아니면 반환됩니다.
+	// 공유 서버나 외부 서버를 사용하는 다른 go test 프로세스의 템플릿이거나, 중단된 이전 실행이 남긴 데이터베이스입니다.
+	ErrTemplateExists = errors.New("template database already exists")
+
+	// ErrRestartUnsupported 외부 서버, 공유 서버, embeddedpostgres.Config로 시작한 서버를 재시작하려 하면 반환됩니다.
+	ErrRestartUnsupported = errors.New("server cannot be restarted")
+)
+
+// StartPhase 서버 시작이 실패한 단계입니다.
+type StartPhase string
+
+const (
+	// StartPhasePort 사용할 포트를 고르는 단계입니다.
+	StartPhasePort StartPhase = "port"
+	// StartPhaseRuntime 런타임 디렉토리를 준비하는 단계입니다.
+	StartPhaseRuntime StartPhase = "runtime"
+	// StartPhaseBinaries PostgreSQL 바이너리를 준비하는 단계입니다.
+	StartPhaseBinaries StartPhase = "binaries"
+	// StartPhaseProcess 서버 프로세스(initdb, pg_ctl start)를 실행하는 단계입니다.
+	StartPhaseProcess StartPhase = "process"
+	// StartPhaseConnect 기본 데이터베이스에 연결하는 단계입니다.
+	StartPhaseConnect StartPhase = "connect"
+	// StartPhaseReady 서버가 쿼리를 처리할 준비가 되었는지 확인하는 단계입니다.
+	StartPhaseReady StartPhase = "ready"
+)
+
+// StartError 서버 시작이 실패한 단계와 포트를 담습니다.
+// 외부 서버(WithDatabaseURL)에서는 연결과 준비 확인 단계에서만 발생합니다.
+type StartError struct {
+	Phase StartPhase
+	Port  uint32 // 사용하려던 포트 (포트를 고르기 전에 실패하면 0)
+	Err   error
+}
+
+// Error 단계와 포트, 원인 오류를 포함한 메시지를 반환합니다.
+func (e *StartError) Error() string {
+	if e.Port == 0 {
+		return fmt.Sprintf("failed to start postgres server (%s): %v", e.Phase, e.Err)
+	}
+	return fmt.Sprintf("failed to start postgres server on port %d (%s): %v", e.Port, e.Phase, e.Err)
+}
+
+// Unwrap 원인 오류를 반환합니다.
+func (e *StartError) Unwrap() error {
+	return e.Err
+}
+
+// DatabaseError 데이터베이스 생성, 연결, 삭제가 실패했을 때 데이터베이스 이름과 SQLSTATE를 담습니다.
+//
+// 사용 예시:
+//
+//	var dbErr *pgtestkit.DatabaseError
+//	if errors.As(err, &dbErr) && dbErr.Code == "55006" {
+//		// 다른 세션이 데이터베이스를 사용 중
+//	}
+type DatabaseError struct {
+	Op       string // 실패한 작업 ("create", "connect", "drop")
+	Database string
+	Code     string // PostgreSQL SQLSTATE (PostgreSQL 오류가 아니면 빈 문자열)
+	Err      error
+}
+
+// Error 작업과 데이터베이스 이름, 원인 오류를 포함한 메시지를 반환합니다.
+func (e *DatabaseError) Error() string {
+	return fmt.Sprintf("failed to %s database %s: %v", e.Op, e.Database, e.Err)
+}
+
+// Unwrap 원인 오류를 반환합니다.
+func (e *DatabaseError) Unwrap() error {
+	return e.Err
+}
+
+// serverNotRunningError 서버가 실행 중이 아닐 때 반환할 오류입니다. 중지된 서버이면 ErrServerStopped도 담습니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func serverNotRunningError() error {
+	if serverStopped {
+		return fmt.Errorf("%w: %w", ErrServerNotRunning, ErrServerStopped)
+	}
+	return ErrServerNotRunning
+}
+
+// newDatabaseError err의 SQLSTATE를 담은 DatabaseError를 만듭니다.
+func newDatabaseError(op, database string, err error) *DatabaseError {
+	return &DatabaseError{Op: op, Database: database, Code: SQLState(err), Err: err}
+}
+
+// SQLState err 체인에 있는 PostgreSQL 오류의 SQLSTATE 코드를 반환합니다. 없으면 빈 문자열입니다.
+func SQLState(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package pgtestkit
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestStartError(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("failed to start server: %w", &StartError{Phase: StartPhaseConnect, Port: 5433, Err: cause})
+
+	var startErr *StartError
+	if !errors.As(err, &startErr) || startErr.Phase != StartPhaseConnect || startErr.Port != 5433 {
+		t.Fatalf("Expected StartError in chain, got %v", err)
+	}
+	if !errors.Is(err, cause) {
+		t.Error("Expected StartError to unwrap to its cause")
+	}
+	want := "failed to start postgres server on port 5433 (connect): connection refused"
+	if startErr.Error() != want {
+		t.Errorf("Error() = %q, want %q", startErr.Error(), want)
+	}
+}
+
+func TestDatabaseError(t *testing.T) {
+	pgErr := &pgconn.PgError{Code: sqlStateObjectInUse, Message: `database "testdb_x" is being accessed by other users`}
+	err := newDatabaseError("drop", "testdb_x", fmt.Errorf("exec: %w", pgErr))
+
+	var dbErr *DatabaseError
+	if !errors.As(fmt.Errorf("close: %w", err), &dbErr) {
+		t.Fatal("Expected DatabaseError in chain")
+	}
+	if dbErr.Op != "drop" || dbErr.Database != "testdb_x" || dbErr.Code != sqlStateObjectInUse {
+		t.Errorf("Unexpected DatabaseError: %+v", dbErr)
+	}
+	if SQLState(err) != sqlStateObjectInUse || !isTemplateInUseError(err) {
+		t.Error("Expected SQLSTATE to be found through the chain")
+	}
+	if code := SQLState(errors.New("object in use")); code != "" {
+		t.Errorf("Expected no SQLSTATE for plain errors, got %q", code)
+	}
+}
+
+// failingConnector Close가 항상 실패하는 커넥터입니다.
+type failingConnector struct{ err error }
+
+func (f *failingConnector) Connect(string) (interface{}, error) { return nil, f.err }
+func (f *failingConnector) Close() error                        { return f.err }
+func (f *failingConnector) Reset() error                        { return f.err }
+
+func TestCloseJoinsErrors(t *testing.T) {
+	cause := errors.New("close failed")
+	c := &DBClient{connector: &failingConnector{err: cause}}
+
+	err := c.Close()
+	if !errors.Is(err, cause) {
+		t.Errorf("Expected Close error to wrap the connector error, got %v", err)
+	}
+}
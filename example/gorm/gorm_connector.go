@@ -2,7 +2,9 @@ package gorm
 
 import (
 	"fmt"
+	"slices"
 
+	"github.com/tidylogic/pgtestkit"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
@@ -61,8 +63,8 @@ func (g *GORMConnector) Reset() error {
 
 	// 모든 테이블 TRUNCATE
 	for _, tableName := range tableNames {
-		if tableName == "schema_migrations" {
-			continue // 마이그레이션 테이블은 건너뜀
+		if slices.Contains(pgtestkit.MigrationTables(), tableName) {
+			continue // 마이그레이션 도구의 버전 테이블은 건너뜀
 		}
 
 		// CASCADE 옵션으로 TRUNCATE 실행
@@ -44,7 +44,7 @@ func (e *ExampleConnector) Reset() error {
 		FROM information_schema.tables 
 		WHERE table_schema = 'public' 
 		AND table_type = 'BASE TABLE'
-		AND table_name != 'schema_migrations'`)
+		AND table_name <> ALL($1)`, pgtestkit.MigrationTables())
 	if err != nil {
 		return fmt.Errorf("failed to query tables: %w", err)
 	}
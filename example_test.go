@@ -1,12 +1,18 @@
 package pgtestkit_test
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/tidylogic/pgtestkit"
 )
@@ -44,7 +50,7 @@ func (e *ExampleConnector) Reset() error {
 		FROM information_schema.tables 
 		WHERE table_schema = 'public' 
 		AND table_type = 'BASE TABLE'
-		AND table_name != 'schema_migrations'`)
+		AND table_name <> ALL($1)`, pgtestkit.MigrationTables())
 	if err != nil {
 		return fmt.Errorf("failed to query tables: %w", err)
 	}
@@ -147,6 +153,879 @@ func TestExample(t *testing.T) {
 	})
 }
 
+func TestTemplateDB(t *testing.T) {
+	err := pgtestkit.CreateTemplateDB("testdb_golden", func(connString string) error {
+		db, err := sql.Open("pgx", connString)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		if _, err := db.Exec("CREATE TABLE countries (code TEXT PRIMARY KEY)"); err != nil {
+			return err
+		}
+		_, err = db.Exec("INSERT INTO countries (code) VALUES ('KR'), ('US')")
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create template DB: %v", err)
+	}
+	defer func() {
+		if err := pgtestkit.SetDefaultTemplate(""); err != nil {
+			t.Errorf("Failed to clear default template: %v", err)
+		}
+	}()
+
+	dbClient, err := pgtestkit.CreateTestDB(&ExampleConnector{}, pgtestkit.WithTemplate("testdb_golden"))
+	if err != nil {
+		t.Fatalf("Failed to create test DB from template: %v", err)
+	}
+	defer dbClient.Close()
+
+	db := dbClient.Client.(*sql.DB)
+
+	// 템플릿의 스키마는 유지되고, Reset으로 데이터만 비워져야 함
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM countries").Scan(&count); err != nil {
+		t.Fatalf("Expected template table to exist: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected 0 rows after reset, got %d", count)
+	}
+}
+
+func TestTemplateDBRecreated(t *testing.T) {
+	createTable := func(table string) func(connString string) error {
+		return func(connString string) error {
+			db, err := sql.Open("pgx", connString)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+			_, err = db.Exec("CREATE TABLE " + table + " (id INT)")
+			return err
+		}
+	}
+	defer func() {
+		if err := pgtestkit.SetDefaultTemplate(""); err != nil {
+			t.Errorf("Failed to clear default template: %v", err)
+		}
+	}()
+
+	// 같은 이름으로 다시 만들면 이전 스키마의 템플릿을 재사용하지 않아야 함
+	if err := pgtestkit.CreateTemplateDB("testdb_recreated", createTable("old_schema")); err != nil {
+		t.Fatalf("Failed to create template DB: %v", err)
+	}
+	if err := pgtestkit.CreateTemplateDB("testdb_recreated", createTable("new_schema")); err != nil {
+		t.Fatalf("Failed to recreate template DB: %v", err)
+	}
+
+	dbClient, err := pgtestkit.CreateTestDB(&ExampleConnector{}, pgtestkit.WithTemplate("testdb_recreated"))
+	if err != nil {
+		t.Fatalf("Failed to create test DB from template: %v", err)
+	}
+	defer dbClient.Close()
+
+	var hasOld, hasNew bool
+	if err := dbClient.Client.(*sql.DB).QueryRow(
+		"SELECT to_regclass('old_schema') IS NOT NULL, to_regclass('new_schema') IS NOT NULL").Scan(&hasOld, &hasNew); err != nil {
+		t.Fatalf("Failed to check tables: %v", err)
+	}
+	if hasOld || !hasNew {
+		t.Errorf("Expected only the new schema in the recreated template, got old=%v new=%v", hasOld, hasNew)
+	}
+}
+
+func TestTemplateDBForeign(t *testing.T) {
+	// 이 프로세스가 템플릿으로 만들지 않은 데이터베이스는 지우지 않아야 함
+	dbClient, err := pgtestkit.CreateTestDB(&ExampleConnector{})
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	defer dbClient.Close()
+
+	err = pgtestkit.CreateTemplateDB(dbClient.DBName, nil)
+	if !errors.Is(err, pgtestkit.ErrTemplateExists) {
+		t.Fatalf("Expected ErrTemplateExists, got %v", err)
+	}
+	if err := dbClient.Client.(*sql.DB).Ping(); err != nil {
+		t.Errorf("Expected the existing database to be left in place: %v", err)
+	}
+}
+
+func TestCreateTestDBContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dbClient, err := pgtestkit.CreateTestDBContext(ctx, &ExampleConnector{})
+	if err == nil {
+		_ = dbClient.Close()
+		t.Fatal("Expected error for canceled context")
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCreateTestDBOf(t *testing.T) {
+	dbClient, err := pgtestkit.CreateTestDBOf(pgtestkit.TypedConnector[*sql.DB](&ExampleConnector{}))
+	if err != nil {
+		t.Fatalf("Failed to create typed test DB: %v", err)
+	}
+	defer dbClient.Close()
+
+	// 타입 단언 없이 *sql.DB를 바로 사용
+	var one int
+	if err := dbClient.Client.QueryRow("SELECT 1").Scan(&one); err != nil {
+		t.Fatalf("Failed to query: %v", err)
+	}
+
+	if _, err := pgtestkit.AsTyped[*sql.Tx](dbClient.DBClient); err == nil {
+		t.Error("Expected error when converting to a mismatched client type")
+	}
+}
+
+func TestNew(t *testing.T) {
+	dbClient, helper := pgtestkit.New(t, &ExampleConnector{})
+
+	// 데이터베이스 이름에 테스트 이름이 포함되어야 함
+	if !strings.HasPrefix(dbClient.DBName, pgtestkit.TestDBPrefix+"testnew_") {
+		t.Errorf("Expected database name to include test name, got %s", dbClient.DBName)
+	}
+
+	helper.MustResetDB(t)
+
+	db := dbClient.Client.(*sql.DB)
+	if err := db.Ping(); err != nil {
+		t.Fatalf("Failed to ping test database: %v", err)
+	}
+}
+
+func TestTxIsolation(t *testing.T) {
+	var firstDB string
+	for _, name := range []string{"first", "second"} {
+		t.Run(name, func(t *testing.T) {
+			dbClient, _ := pgtestkit.New(t, &pgtestkit.TxSQLConnector{}, pgtestkit.WithTxIsolation())
+			db := dbClient.Client.(*sql.DB)
+
+			// 두 테스트는 같은 데이터베이스를 공유하지만 서로의 변경은 보이지 않아야 함
+			if firstDB == "" {
+				firstDB = dbClient.DBName
+			} else if dbClient.DBName != firstDB {
+				t.Errorf("Expected shared database %s, got %s", firstDB, dbClient.DBName)
+			}
+			if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS items (id INT PRIMARY KEY)`); err != nil {
+				t.Fatalf("Failed to create table: %v", err)
+			}
+
+			// 애플리케이션의 트랜잭션은 세이브포인트로 동작
+			tx, err := db.Begin()
+			if err != nil {
+				t.Fatalf("Failed to begin transaction: %v", err)
+			}
+			if _, err := tx.Exec(`INSERT INTO items (id) VALUES (1)`); err != nil {
+				t.Fatalf("Failed to insert: %v", err)
+			}
+			if err := tx.Commit(); err != nil {
+				t.Fatalf("Failed to commit: %v", err)
+			}
+
+			// 실패한 문장은 테스트 트랜잭션을 중단시키지 않음
+			if _, err := db.Exec(`INSERT INTO items (id) VALUES (1)`); err == nil {
+				t.Fatal("Expected duplicate key error")
+			}
+
+			var count int
+			if err := db.QueryRow(`SELECT count(*) FROM items`).Scan(&count); err != nil {
+				t.Fatalf("Failed to count rows: %v", err)
+			}
+			if count != 1 {
+				t.Errorf("Expected 1 row, got %d", count)
+			}
+		})
+	}
+}
+
+func TestTxIsolationQueries(t *testing.T) {
+	dbClient, _ := pgtestkit.New(t, &pgtestkit.TxSQLConnector{}, pgtestkit.WithTxIsolation())
+	db := dbClient.Client.(*sql.DB)
+
+	if _, err := db.Exec(`CREATE TABLE IF NOT EXISTS tags (id INT PRIMARY KEY)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO tags (id) VALUES (1), (2)`); err != nil {
+		t.Fatalf("Failed to insert: %v", err)
+	}
+
+	// 실패한 QueryRow도 테스트 트랜잭션을 중단시키지 않음
+	var id int
+	if err := db.QueryRow(`INSERT INTO tags (id) VALUES (1) RETURNING id`).Scan(&id); err == nil {
+		t.Fatal("Expected duplicate key error")
+	}
+	if err := db.QueryRow(`INSERT INTO tags (id) VALUES (3) RETURNING id`).Scan(&id); err != nil {
+		t.Fatalf("Failed to insert after a failed query: %v", err)
+	}
+
+	// 준비된 문장도 마찬가지
+	stmt, err := db.Prepare(`INSERT INTO tags (id) VALUES ($1)`)
+	if err != nil {
+		t.Fatalf("Failed to prepare: %v", err)
+	}
+	defer stmt.Close()
+	if _, err := stmt.Exec(1); err == nil {
+		t.Fatal("Expected duplicate key error")
+	}
+	if _, err := stmt.Exec(4); err != nil {
+		t.Fatalf("Failed to execute prepared statement after a failure: %v", err)
+	}
+
+	// rows를 읽는 도중에도 같은 트랜잭션에서 문장을 실행할 수 있음
+	rows, err := db.Query(`SELECT id FROM tags ORDER BY id`)
+	if err != nil {
+		t.Fatalf("Failed to query: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		if err := rows.Scan(&id); err != nil {
+			t.Fatalf("Failed to scan: %v", err)
+		}
+		if _, err := db.Exec(`UPDATE tags SET id = id + 100 WHERE id = $1`, id); err != nil {
+			t.Fatalf("Failed to update inside rows loop: %v", err)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Failed to read rows: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT count(*) FROM tags WHERE id > 100`).Scan(&count); err != nil {
+		t.Fatalf("Failed to count rows: %v", err)
+	}
+	if count != 4 {
+		t.Errorf("Expected 4 updated rows, got %d", count)
+	}
+}
+
+func TestTxIsolationPgx(t *testing.T) {
+	ctx := context.Background()
+	dbClient, err := pgtestkit.CreateTestDBOf(pgtestkit.TypedConnector[pgx.Tx](&pgtestkit.TxPgxConnector{}),
+		pgtestkit.WithTxIsolation())
+	if err != nil {
+		t.Fatalf("Failed to create test transaction: %v", err)
+	}
+	tx := dbClient.Client
+
+	if _, err := tx.Exec(ctx, `CREATE TABLE pgx_items (id INT PRIMARY KEY)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := tx.Exec(ctx, `INSERT INTO pgx_items (id) VALUES (1)`); err != nil {
+		t.Fatalf("Failed to insert: %v", err)
+	}
+
+	// 실패한 문장은 테스트 트랜잭션을 중단시키지 않음
+	if _, err := tx.Exec(ctx, `INSERT INTO pgx_items (id) VALUES (1)`); err == nil {
+		t.Fatal("Expected duplicate key error")
+	}
+
+	// 중첩 트랜잭션은 세이브포인트로 동작
+	nested, err := tx.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Failed to begin nested transaction: %v", err)
+	}
+	if _, err := nested.Exec(ctx, `INSERT INTO pgx_items (id) VALUES (2)`); err != nil {
+		t.Fatalf("Failed to insert in nested transaction: %v", err)
+	}
+	if err := nested.Rollback(ctx); err != nil {
+		t.Fatalf("Failed to roll back nested transaction: %v", err)
+	}
+	if err := nested.Rollback(ctx); !errors.Is(err, pgx.ErrTxClosed) {
+		t.Errorf("Expected pgx.ErrTxClosed for an ended transaction, got %v", err)
+	}
+
+	// 테스트 트랜잭션의 Commit과 COMMIT 문장은 실제로 커밋하지 않음
+	if err := tx.Commit(ctx); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if _, err := tx.Exec(ctx, "COMMIT"); err != nil {
+		t.Fatalf("Failed to execute COMMIT: %v", err)
+	}
+	ids, err := pgx.CollectRows(mustQuery(t, tx, `SELECT id FROM pgx_items ORDER BY id`), pgx.RowTo[int])
+	if err != nil {
+		t.Fatalf("Failed to read rows: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != 1 {
+		t.Errorf("Expected only row 1, got %v", ids)
+	}
+	if err := dbClient.Close(); err != nil {
+		t.Fatalf("Failed to close test transaction: %v", err)
+	}
+
+	// 같은 공유 데이터베이스를 사용하는 다음 테스트에는 테이블이 남아 있지 않아야 함
+	next, _ := pgtestkit.NewOf(t, pgtestkit.TypedConnector[pgx.Tx](&pgtestkit.TxPgxConnector{}), pgtestkit.WithTxIsolation())
+	var exists bool
+	if err := next.Client.QueryRow(ctx, `SELECT to_regclass('pgx_items') IS NOT NULL`).Scan(&exists); err != nil {
+		t.Fatalf("Failed to check table: %v", err)
+	}
+	if exists {
+		t.Error("Expected the committed table to be rolled back by Close")
+	}
+}
+
+func mustQuery(t *testing.T, tx pgx.Tx, query string) pgx.Rows {
+	t.Helper()
+	rows, err := tx.Query(context.Background(), query)
+	if err != nil {
+		t.Fatalf("Failed to query: %v", err)
+	}
+	return rows
+}
+
+func TestTxIsolationTxOrder(t *testing.T) {
+	dbClient, _ := pgtestkit.New(t, &pgtestkit.TxSQLConnector{}, pgtestkit.WithTxIsolation())
+	db := dbClient.Client.(*sql.DB)
+
+	first, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+	second, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// 나중에 시작한 트랜잭션이 열려 있으면 먼저 시작한 트랜잭션을 끝낼 수 없음
+	if err := first.Commit(); err == nil {
+		t.Error("Expected committing the outer transaction first to fail")
+	}
+	if err := second.Commit(); err != nil {
+		t.Errorf("Failed to commit the inner transaction: %v", err)
+	}
+}
+
+func TestSchemaIsolation(t *testing.T) {
+	err := pgtestkit.CreateTemplateSchema("example_template", func(connString string) error {
+		db, err := sql.Open("pgx", connString)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		_, err = db.Exec(`
+			CREATE TABLE authors (id SERIAL PRIMARY KEY, name TEXT NOT NULL);
+			CREATE TABLE books (id INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY, author_id INT REFERENCES authors (id));
+			INSERT INTO authors (name) VALUES ('Ann');
+			INSERT INTO books (author_id) VALUES (1);`)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to create template schema: %v", err)
+	}
+
+	dbClient, _ := pgtestkit.New(t, &ExampleConnector{}, pgtestkit.WithSchemaIsolation())
+	db := dbClient.Client.(*sql.DB)
+	if dbClient.Schema == "" {
+		t.Fatal("Expected a test schema")
+	}
+
+	// 템플릿 스키마의 데이터와 시퀀스가 복제되어야 함
+	var id int
+	if err := db.QueryRow(`INSERT INTO authors (name) VALUES ('Bob') RETURNING id`).Scan(&id); err != nil {
+		t.Fatalf("Failed to insert author: %v", err)
+	}
+	if id != 2 {
+		t.Errorf("Expected cloned sequence to continue at 2, got %d", id)
+	}
+	if err := db.QueryRow(`INSERT INTO books (author_id) VALUES (2) RETURNING id`).Scan(&id); err != nil {
+		t.Fatalf("Failed to insert book: %v", err)
+	}
+	if id != 2 {
+		t.Errorf("Expected cloned identity to continue at 2, got %d", id)
+	}
+
+	// 외래 키가 복제된 스키마의 테이블을 참조해야 함
+	if _, err := db.Exec(`INSERT INTO books (author_id) VALUES (99)`); err == nil {
+		t.Error("Expected foreign key violation")
+	}
+
+	// 시리얼 컬럼의 시퀀스는 복제된 테이블이 소유해야 함
+	var owner sql.NullString
+	if err := db.QueryRow(`SELECT pg_get_serial_sequence($1, 'id')`, dbClient.Schema+".authors").Scan(&owner); err != nil {
+		t.Fatalf("Failed to read sequence owner: %v", err)
+	}
+	if !owner.Valid || !strings.HasPrefix(owner.String, dbClient.Schema+".") {
+		t.Errorf("Expected authors.id to own a sequence in %s, got %q", dbClient.Schema, owner.String)
+	}
+	if _, err := db.Exec(`DROP TABLE books, authors`); err != nil {
+		t.Fatalf("Failed to drop tables: %v", err)
+	}
+	var sequences int
+	if err := db.QueryRow(`SELECT count(*) FROM pg_sequences WHERE schemaname = $1`, dbClient.Schema).Scan(&sequences); err != nil {
+		t.Fatalf("Failed to count sequences: %v", err)
+	}
+	if sequences != 0 {
+		t.Errorf("Expected owned sequences to be dropped with their tables, %d left", sequences)
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	migrations := fstest.MapFS{
+		"001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id SERIAL PRIMARY KEY, name TEXT NOT NULL);")},
+		"001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"002_seed_users.up.sql":     {Data: []byte("INSERT INTO users (name) VALUES ('admin');")},
+	}
+
+	dbClient, _ := pgtestkit.New(t, &ExampleConnector{}, pgtestkit.WithMigrations(migrations))
+	db := dbClient.Client.(*sql.DB)
+
+	var applied int
+	if err := db.QueryRow(`SELECT count(*) FROM ` + pgtestkit.DefaultMigrationsTable).Scan(&applied); err != nil {
+		t.Fatalf("Failed to read migrations table: %v", err)
+	}
+	if applied != 2 {
+		t.Errorf("Expected 2 applied migrations, got %d", applied)
+	}
+
+	// 실패한 파일과 위치가 오류에 포함되어야 함
+	broken := fstest.MapFS{
+		"001_broken.up.sql": {Data: []byte("CREATE TABLE ok (id INT);\nSELECT * FROM missing_table;")},
+	}
+	err := pgtestkit.Migrate(context.Background(), db, broken, pgtestkit.WithMigrationsTable("broken_migrations"))
+	var migErr *pgtestkit.MigrationError
+	if !errors.As(err, &migErr) {
+		t.Fatalf("Expected MigrationError, got %v", err)
+	}
+	if migErr.File != "001_broken.up.sql" || migErr.StatementIndex != 2 || migErr.Line != 2 || migErr.Column != 15 {
+		t.Errorf("Unexpected error location: %+v", migErr)
+	}
+}
+
+func TestVerifyMigrations(t *testing.T) {
+	migrations := fstest.MapFS{
+		"001_create_users.up.sql":   {Data: []byte("CREATE TABLE users (id SERIAL PRIMARY KEY, name TEXT NOT NULL);")},
+		"001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"002_add_email.up.sql":      {Data: []byte("ALTER TABLE users ADD COLUMN email TEXT;")},
+		"002_add_email.down.sql":    {Data: []byte("ALTER TABLE users DROP COLUMN email;")},
+	}
+	pgtestkit.VerifyMigrations(t, migrations)
+
+	// down이 up을 정확히 되돌리지 않으면 스키마 차이가 보고되어야 함
+	migrations["002_add_email.down.sql"] = &fstest.MapFile{
+		Data: []byte("ALTER TABLE users DROP COLUMN email; ALTER TABLE users ADD COLUMN nickname TEXT;"),
+	}
+	err := pgtestkit.VerifyMigrationsContext(context.Background(), migrations)
+	if err == nil || !strings.Contains(err.Error(), "+     column nickname text") {
+		t.Errorf("Expected schema diff mentioning the extra column, got %v", err)
+	}
+}
+
+func TestResetStrategies(t *testing.T) {
+	migrations := fstest.MapFS{
+		"001_create_tables.up.sql": {Data: []byte(`
+			CREATE TABLE authors (id SERIAL PRIMARY KEY, name TEXT NOT NULL);
+			CREATE TABLE books (id INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY, author_id INT REFERENCES authors (id));
+			CREATE TABLE countries (code TEXT PRIMARY KEY);
+			INSERT INTO countries (code) VALUES ('KR');`)},
+	}
+	strategies := map[string]pgtestkit.ResetStrategy{
+		"truncate": pgtestkit.TruncateTables(pgtestkit.WithResetExcludeTables(pgtestkit.DefaultMigrationsTable, "countries")),
+		"delete":   pgtestkit.DeleteTables(pgtestkit.WithResetExcludeTables(pgtestkit.DefaultMigrationsTable, "countries")),
+		"schema":   pgtestkit.RecreateSchemas(),
+		"database": pgtestkit.RecreateDatabase(),
+	}
+	for name, strategy := range strategies {
+		t.Run(name, func(t *testing.T) {
+			dbClient, helper := pgtestkit.New(t, &ExampleConnector{},
+				pgtestkit.WithMigrations(migrations), pgtestkit.WithResetStrategy(strategy))
+			db := dbClient.Client.(*sql.DB)
+			if _, err := db.Exec(`INSERT INTO authors (name) VALUES ('Ann'); INSERT INTO books (author_id) VALUES (1);`); err != nil {
+				t.Fatalf("Failed to insert rows: %v", err)
+			}
+
+			helper.MustResetDB(t)
+
+			// RecreateDatabase는 커넥터를 다시 연결하므로 클라이언트를 다시 읽음
+			db = dbClient.Client.(*sql.DB)
+			var authors, countries, id int
+			if err := db.QueryRow(`SELECT (SELECT count(*) FROM authors), (SELECT count(*) FROM countries)`).Scan(&authors, &countries); err != nil {
+				t.Fatalf("Failed to count rows: %v", err)
+			}
+			if authors != 0 || countries != 1 {
+				t.Errorf("Expected 0 authors and 1 country after reset, got %d and %d", authors, countries)
+			}
+
+			// 시퀀스와 identity도 처음부터 다시 시작해야 함
+			if err := db.QueryRow(`INSERT INTO authors (name) VALUES ('Bob') RETURNING id`).Scan(&id); err != nil {
+				t.Fatalf("Failed to insert author: %v", err)
+			}
+			if id != 1 {
+				t.Errorf("Expected sequence to restart at 1, got %d", id)
+			}
+			if err := db.QueryRow(`INSERT INTO books (author_id) VALUES (1) RETURNING id`).Scan(&id); err != nil {
+				t.Fatalf("Failed to insert book: %v", err)
+			}
+			if id != 1 {
+				t.Errorf("Expected identity to restart at 1, got %d", id)
+			}
+		})
+	}
+}
+
+func TestTruncateDirtyTables(t *testing.T) {
+	migrations := fstest.MapFS{
+		"001_create_tables.up.sql": {Data: []byte(`
+			CREATE TABLE authors (id SERIAL PRIMARY KEY, name TEXT NOT NULL);
+			CREATE TABLE books (id INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY, author_id INT REFERENCES authors (id));
+			CREATE TABLE countries (code TEXT PRIMARY KEY);
+			INSERT INTO countries (code) VALUES ('KR');`)},
+	}
+	dbClient, helper := pgtestkit.New(t, &ExampleConnector{},
+		pgtestkit.WithMigrations(migrations), pgtestkit.WithResetStrategy(pgtestkit.TruncateDirtyTables()))
+	db := dbClient.Client.(*sql.DB)
+
+	if _, err := db.Exec(`INSERT INTO authors (name) VALUES ('Ann')`); err != nil {
+		t.Fatalf("Failed to insert author: %v", err)
+	}
+	if _, err := db.Exec(`CREATE TABLE notes (body TEXT); INSERT INTO notes VALUES ('created during the test')`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	helper.MustResetDB(t)
+
+	// 변경된 테이블과 트리거가 없던 테이블만 비워지고, 건드리지 않은 countries는 그대로여야 함
+	var authors, notes, countries, id int
+	if err := db.QueryRow(`SELECT (SELECT count(*) FROM authors), (SELECT count(*) FROM notes), (SELECT count(*) FROM countries)`).
+		Scan(&authors, &notes, &countries); err != nil {
+		t.Fatalf("Failed to count rows: %v", err)
+	}
+	if authors != 0 || notes != 0 || countries != 1 {
+		t.Errorf("Expected 0 authors, 0 notes and 1 country, got %d, %d and %d", authors, notes, countries)
+	}
+	if err := db.QueryRow(`INSERT INTO authors (name) VALUES ('Bob') RETURNING id`).Scan(&id); err != nil {
+		t.Fatalf("Failed to insert author: %v", err)
+	}
+	if id != 1 {
+		t.Errorf("Expected sequence to restart at 1, got %d", id)
+	}
+
+	// 새로 만든 테이블도 이후에는 추적되어야 함
+	if _, err := db.Exec(`INSERT INTO notes VALUES ('tracked')`); err != nil {
+		t.Fatalf("Failed to insert note: %v", err)
+	}
+	helper.MustResetDB(t)
+	if err := db.QueryRow(`SELECT count(*) FROM notes`).Scan(&notes); err != nil {
+		t.Fatalf("Failed to count notes: %v", err)
+	}
+	if notes != 0 {
+		t.Errorf("Expected notes to be truncated, got %d rows", notes)
+	}
+}
+
+func TestLoadFixtures(t *testing.T) {
+	migrations := fstest.MapFS{
+		"001_create_tables.up.sql": {Data: []byte(`
+			CREATE TABLE users (id SERIAL PRIMARY KEY, name TEXT NOT NULL, tags TEXT[], settings JSONB, avatar BYTEA);
+			CREATE TABLE posts (id INT GENERATED ALWAYS AS IDENTITY PRIMARY KEY, author_id INT NOT NULL REFERENCES users (id),
+				title TEXT NOT NULL, published_at TIMESTAMPTZ);`)},
+	}
+	fixtures := fstest.MapFS{
+		// posts가 먼저 오더라도 외래 키를 따라 users부터 적재되어야 함
+		"posts.csv": {Data: []byte("author_id,title,published_at\n$ref:users.bob,Hello,2024-01-02T03:04:05Z\n$ref:users.alice,Draft,\n")},
+		"users.yml": {Data: []byte(`
+alice:
+  id: 10
+  name: Alice
+  tags: [admin, staff]
+  settings: {theme: dark, beta: true}
+  avatar: '\x0102'
+bob:
+  id: 20
+  name: Bob
+`)},
+	}
+	dbClient, _ := pgtestkit.New(t, &ExampleConnector{}, pgtestkit.WithMigrations(migrations))
+	db := dbClient.Client.(*sql.DB)
+
+	if err := pgtestkit.LoadFixtures(db, fixtures); err != nil {
+		t.Fatalf("Failed to load fixtures: %v", err)
+	}
+
+	var tags, theme string
+	var avatar []byte
+	if err := db.QueryRow(`SELECT array_to_string(tags, ','), settings->>'theme', avatar FROM users WHERE name = 'Alice'`).
+		Scan(&tags, &theme, &avatar); err != nil {
+		t.Fatalf("Failed to read user: %v", err)
+	}
+	if tags != "admin,staff" || theme != "dark" || len(avatar) != 2 {
+		t.Errorf("Unexpected user columns: tags=%q theme=%q avatar=%v", tags, theme, avatar)
+	}
+
+	var author string
+	var published sql.NullTime
+	if err := db.QueryRow(`SELECT u.name, p.published_at FROM posts p JOIN users u ON u.id = p.author_id WHERE p.title = 'Hello'`).
+		Scan(&author, &published); err != nil {
+		t.Fatalf("Failed to read post: %v", err)
+	}
+	if author != "Bob" || !published.Valid || published.Time.Year() != 2024 {
+		t.Errorf("Unexpected post: author=%q published=%v", author, published)
+	}
+
+	// 시퀀스는 픽스처의 최댓값 이후로 옮겨져야 함
+	var id int
+	if err := db.QueryRow(`INSERT INTO users (name) VALUES ('Carol') RETURNING id`).Scan(&id); err != nil {
+		t.Fatalf("Failed to insert user: %v", err)
+	}
+	if id != 21 {
+		t.Errorf("Expected sequence to continue at 21, got %d", id)
+	}
+	if err := db.QueryRow(`INSERT INTO posts (author_id, title) VALUES (10, 'Next') RETURNING id`).Scan(&id); err != nil {
+		t.Fatalf("Failed to insert post: %v", err)
+	}
+	if id != 3 {
+		t.Errorf("Expected identity to continue at 3, got %d", id)
+	}
+}
+
+func TestFactories(t *testing.T) {
+	migrations := fstest.MapFS{
+		"001_create_tables.up.sql": {Data: []byte(`
+			CREATE TYPE account_status AS ENUM ('active', 'banned');
+			CREATE TABLE accounts (id BIGSERIAL PRIMARY KEY, email TEXT NOT NULL UNIQUE, handle VARCHAR(12) NOT NULL UNIQUE,
+				status account_status NOT NULL, role TEXT NOT NULL, created_at TIMESTAMPTZ NOT NULL, bio TEXT);
+			CREATE TABLE articles (id UUID PRIMARY KEY, account_id BIGINT NOT NULL REFERENCES accounts (id),
+				editor_id BIGINT REFERENCES accounts (id), title TEXT NOT NULL, published BOOLEAN NOT NULL);`)},
+	}
+	dbClient, _ := pgtestkit.New(t, &ExampleConnector{}, pgtestkit.WithMigrations(migrations))
+	db := dbClient.Client.(*sql.DB)
+
+	type account struct {
+		Role string
+		Bio  string `db:"bio"`
+	}
+	accounts := pgtestkit.DefineFactory("accounts",
+		pgtestkit.WithDefaults(account{Role: "member"}),
+		pgtestkit.WithSequence("email", "user%d@example.com"))
+	articles := pgtestkit.NewFactory("articles",
+		pgtestkit.WithAssociation("editor_id", "accounts", map[string]interface{}{"role": "editor"}))
+
+	admin := accounts.Create(t, db, account{Role: "admin"})
+	if admin["role"] != "admin" || admin["status"] != "active" || admin["bio"] != nil {
+		t.Errorf("Unexpected account: %v", admin)
+	}
+	if email, _ := admin["email"].(string); !strings.HasPrefix(email, "user") {
+		t.Errorf("Expected sequenced email, got %v", admin["email"])
+	}
+
+	// 필수 외래 키(account_id)는 등록된 accounts 팩토리로, editor_id는 연관으로 부모 행을 만듦
+	many := articles.CreateMany(t, db, 3, map[string]interface{}{"title": "Hello"})
+	if len(many) != 3 || many[0]["title"] != "Hello" || many[0]["published"] != false {
+		t.Fatalf("Unexpected articles: %v", many)
+	}
+	var members, editors int
+	if err := db.QueryRow(`SELECT count(*) FILTER (WHERE role = 'member'), count(*) FILTER (WHERE role = 'editor') FROM accounts`).
+		Scan(&members, &editors); err != nil {
+		t.Fatalf("Failed to count accounts: %v", err)
+	}
+	if members != 3 || editors != 3 {
+		t.Errorf("Expected 3 members and 3 editors, got %d and %d", members, editors)
+	}
+
+	// 팩토리를 정의하지 않은 테이블도 필수 컬럼을 채워 만들 수 있음
+	article := pgtestkit.Create(t, db, "public.articles", map[string]interface{}{"account_id": admin["id"]})
+	if article["account_id"] != admin["id"] || article["editor_id"] != nil {
+		t.Errorf("Unexpected article: %v", article)
+	}
+}
+
+// recordingTB는 Assert* 메서드가 보고한 실패를 기록합니다.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// failingTB는 실패한 테스트처럼 동작합니다.
+type failingTB struct {
+	testing.TB
+}
+
+func (f *failingTB) Failed() bool { return true }
+
+func TestKeepOnFailure(t *testing.T) {
+	dbClient, err := pgtestkit.CreateTestDB(&ExampleConnector{},
+		pgtestkit.WithTB(&failingTB{TB: t}), pgtestkit.WithKeepOnFailure(true))
+	if err != nil {
+		t.Fatalf("Failed to create test DB: %v", err)
+	}
+	if err := dbClient.Close(); err != nil {
+		t.Fatalf("Failed to close test DB: %v", err)
+	}
+
+	// 실패한 테스트의 데이터베이스는 Close 뒤에도 남아 있어야 함
+	db, err := sql.Open("pgx", dbClient.ConnectionString)
+	if err != nil {
+		t.Fatalf("Failed to open kept database: %v", err)
+	}
+	if err := db.Ping(); err != nil {
+		t.Errorf("Expected database %s to survive Close: %v", dbClient.DBName, err)
+	}
+	db.Close()
+
+	// 직접 삭제하면 StopPostgres가 데이터 디렉토리를 보존하지 않음
+	admin, err := sql.Open("pgx", strings.Replace(dbClient.ConnectionString, "/"+dbClient.DBName, "/"+pgtestkit.DefaultDB, 1))
+	if err != nil {
+		t.Fatalf("Failed to open admin connection: %v", err)
+	}
+	defer admin.Close()
+	if _, err := admin.Exec(`DROP DATABASE ` + dbClient.DBName + ` WITH (FORCE)`); err != nil {
+		t.Fatalf("Failed to drop kept database: %v", err)
+	}
+}
+
+// logRecordingTB는 t.Log로 전달된 줄을 기록합니다.
+type logRecordingTB struct {
+	testing.TB
+	mu   sync.Mutex
+	logs []string
+}
+
+func (r *logRecordingTB) Log(args ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.logs = append(r.logs, fmt.Sprint(args...))
+}
+
+func TestServerLogs(t *testing.T) {
+	rec := &logRecordingTB{TB: t}
+	dbClient, err := pgtestkit.CreateTestDB(&ExampleConnector{}, pgtestkit.WithTB(rec))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	db := dbClient.Client.(*sql.DB)
+
+	if _, err := db.Exec("SELECT * FROM missing_table"); err == nil {
+		t.Fatal("Expected query on missing table to fail")
+	}
+	// 다른 데이터베이스의 로그는 전달되지 않아야 함
+	other, _ := pgtestkit.New(t, &ExampleConnector{}, pgtestkit.WithServerLogs(false))
+	_, _ = other.Client.(*sql.DB).Exec("SELECT * FROM other_missing_table")
+
+	// 로그 수집 프로세스가 파일에 쓰는 것을 기다림
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+		rec.mu.Lock()
+		logs := strings.Join(rec.logs, "\n")
+		rec.mu.Unlock()
+		if strings.Contains(logs, "STATEMENT:") {
+			break
+		}
+	}
+	if err := dbClient.Close(); err != nil {
+		t.Fatalf("Failed to close test database: %v", err)
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	logs := strings.Join(rec.logs, "\n")
+	if !strings.Contains(logs, `relation "missing_table" does not exist`) || !strings.Contains(logs, "STATEMENT:  SELECT * FROM missing_table") {
+		t.Errorf("Expected server error and statement in test log, got:\n%s", logs)
+	}
+	if strings.Contains(logs, "other_missing_table") {
+		t.Errorf("Expected logs of other databases to be filtered, got:\n%s", logs)
+	}
+}
+
+func TestAssertions(t *testing.T) {
+	migrations := fstest.MapFS{
+		"001_create_users.up.sql": {Data: []byte(`
+			CREATE TABLE users (id INT PRIMARY KEY, name TEXT NOT NULL, email TEXT, created_at TIMESTAMPTZ NOT NULL DEFAULT now());
+			CREATE TABLE audit_log (id SERIAL PRIMARY KEY, message TEXT);
+			INSERT INTO users (id, name, email) VALUES (1, 'Alice', 'alice@example.com'), (2, 'Bob', NULL);`)},
+	}
+	_, helper := pgtestkit.New(t, &ExampleConnector{}, pgtestkit.WithMigrations(migrations))
+
+	helper.AssertRowCount(t, "users", 2)
+	helper.AssertRowExists(t, "users", map[string]interface{}{"id": "1", "name": "Alice"})
+	helper.AssertRowExists(t, "public.users", map[string]interface{}{"name": "Bob", "email": nil})
+	helper.AssertNoRows(t, "users", map[string]interface{}{"name": "Carol"})
+	helper.AssertNoRows(t, "audit_log", nil)
+
+	// 순서를 무시하고, created_at처럼 기대하는 행에 없는 컬럼은 비교하지 않음
+	helper.AssertTableEquals(t, "users", []map[string]interface{}{
+		{"id": 2, "name": "Bob", "email": nil},
+		{"id": 1, "name": "Alice", "email": "alice@example.com"},
+	})
+	helper.AssertTableEquals(t, "users", []map[string]interface{}{
+		{"id": 2},
+		{"id": 1},
+	}, pgtestkit.WithTableColumns("id"), pgtestkit.WithTableOrderBy("id DESC"))
+
+	rec := &recordingTB{TB: t}
+	helper.AssertRowCount(rec, "users", 3)
+	helper.AssertRowExists(rec, "users", map[string]interface{}{"name": "Carol"})
+	helper.AssertNoRows(rec, "users", nil)
+	helper.AssertTableEquals(rec, "users", []map[string]interface{}{
+		{"id": 1, "name": "Alice"},
+		{"id": 2, "name": "Robert"},
+	}, pgtestkit.WithTableOrderBy("id"))
+	if len(rec.errors) != 4 {
+		t.Fatalf("Expected 4 assertion failures, got %d: %q", len(rec.errors), rec.errors)
+	}
+	if !strings.Contains(rec.errors[1], "Alice") {
+		t.Errorf("Expected failure to include table contents, got:\n%s", rec.errors[1])
+	}
+	if !strings.Contains(rec.errors[3], "- 2  | Robert") || !strings.Contains(rec.errors[3], "+ 2  | Bob") {
+		t.Errorf("Expected table diff, got:\n%s", rec.errors[3])
+	}
+}
+
+func TestSchemaGolden(t *testing.T) {
+	migrations := fstest.MapFS{
+		"001_create_users.up.sql": {Data: []byte("CREATE TABLE users (id SERIAL PRIMARY KEY, name TEXT NOT NULL);")},
+	}
+	dbClient, _ := pgtestkit.New(t, &ExampleConnector{}, pgtestkit.WithMigrations(migrations))
+
+	// go test -update로 testdata/users_schema.golden을 다시 쓸 수 있음
+	pgtestkit.AssertSchemaGolden(t, dbClient.Client.(*sql.DB), "users_schema.golden")
+}
+
+func TestDBPool(t *testing.T) {
+	if err := pgtestkit.EnableDBPool(pgtestkit.WithPoolSize(2)); err != nil {
+		t.Fatalf("Failed to enable database pool: %v", err)
+	}
+	// 이후 테스트가 풀의 영향을 받지 않도록 정리
+	t.Cleanup(func() {
+		if err := pgtestkit.DisableDBPool(); err != nil {
+			t.Errorf("Failed to disable database pool: %v", err)
+		}
+		if stats := pgtestkit.DBPoolStats(); stats.Size != 0 {
+			t.Errorf("Expected the pool to be disabled, got %+v", stats)
+		}
+	})
+
+	// 백그라운드에서 풀이 채워질 때까지 대기
+	deadline := time.Now().Add(30 * time.Second)
+	for pgtestkit.DBPoolStats().Ready == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("Database pool was not filled: %+v", pgtestkit.DBPoolStats())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	dbClient, _ := pgtestkit.New(t, &ExampleConnector{})
+
+	// 풀에서 가져온 데이터베이스도 테스트 이름으로 바뀌어야 함
+	if !strings.HasPrefix(dbClient.DBName, pgtestkit.TestDBPrefix+"testdbpool_") {
+		t.Errorf("Expected database name to include test name, got %s", dbClient.DBName)
+	}
+	if stats := pgtestkit.DBPoolStats(); stats.Hits != 1 {
+		t.Errorf("Expected 1 pool hit, got %+v", stats)
+	}
+}
+
 func TestMain(m *testing.M) {
 	// 모든 테스트에 대해 DB 서버 자동 관리
 	os.Exit(pgtestkit.TestMainWrapper(m, nil))
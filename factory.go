@@ -0,0 +1,575 @@
+package pgtestkit
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap"
+)
+
+// 등록된 팩토리 (DefineFactory에 전달한 테이블 이름별)
+var factories sync.Map
+
+// Row 팩토리가 넣은 행입니다. 컬럼 이름별로 RETURNING *의 값을 담습니다.
+type Row map[string]interface{}
+
+// Factory 테이블 하나의 행을 만드는 팩토리입니다. 기본값, 순번으로 만드는 고유 값, 부모 행을 만드는 연관을 정의하며
+// 여러 테스트에서 동시에 사용할 수 있습니다.
+//
+// 행을 만들 때 값은 다음 순서로 정해집니다: overrides, WithDefaults, WithSequence, WithAssociation.
+// 그래도 값이 없는 NOT NULL 컬럼 중 기본값, identity, 생성 컬럼이 아닌 컬럼은 자동으로 채웁니다.
+// 단일 컬럼 외래 키이면 부모 테이블의 팩토리로 부모 행을 만들고, 그 밖에는 컬럼 타입에 맞는 값을 만듭니다.
+type Factory struct {
+	table        string
+	defaults     map[string]interface{}
+	sequences    map[string]func(n int) interface{}
+	associations map[string]factoryAssociation
+	err          error // 옵션 오류 (행을 만들 때 보고)
+
+	counter atomic.Int64
+}
+
+// factoryAssociation 컬럼 값을 얻기 위해 만들 부모 행입니다.
+type factoryAssociation struct {
+	table     string
+	overrides interface{}
+}
+
+// FactoryOption 팩토리를 설정하는 옵션입니다.
+type FactoryOption func(*Factory)
+
+// WithDefaults 행의 기본값을 지정합니다. values는 map[string]interface{} 또는 구조체(포인터)이며,
+// 구조체 필드는 `db` 태그나 snake_case로 바꾼 필드 이름의 컬럼이 됩니다. 구조체의 zero 값 필드는 무시합니다.
+func WithDefaults(values interface{}) FactoryOption {
+	return func(f *Factory) {
+		m, err := factoryValues(values)
+		if err != nil {
+			f.err = fmt.Errorf("invalid defaults: %w", err)
+			return
+		}
+		for name, value := range m {
+			f.defaults[name] = value
+		}
+	}
+}
+
+// WithSequence column에 format의 %d를 팩토리의 순번(1부터)으로 바꾼 고유 값을 넣습니다.
+// 예: WithSequence("email", "user%d@example.com")
+func WithSequence(column, format string) FactoryOption {
+	return WithSequenceFunc(column, func(n int) interface{} {
+		return fmt.Sprintf(format, n)
+	})
+}
+
+// WithSequenceFunc column에 팩토리의 순번(1부터)으로 만든 값을 넣습니다.
+func WithSequenceFunc(column string, fn func(n int) interface{}) FactoryOption {
+	return func(f *Factory) {
+		f.sequences[column] = fn
+	}
+}
+
+// WithAssociation column 값이 없으면 table의 팩토리로 부모 행을 만들고, 외래 키가 참조하는 컬럼
+// (외래 키가 없으면 부모의 기본 키) 값을 넣습니다. overrides는 부모 행에 전달됩니다.
+func WithAssociation(column, table string, overrides interface{}) FactoryOption {
+	return func(f *Factory) {
+		f.associations[column] = factoryAssociation{table: table, overrides: overrides}
+	}
+}
+
+// DefineFactory table("name" 또는 "schema.name")의 팩토리를 정의하고 등록합니다.
+// Create와 CreateMany, 그리고 다른 팩토리가 부모 행을 만들 때 사용되며, 같은 테이블을 다시 정의하면 교체됩니다.
+// 보통 TestMain이나 패키지 변수에서 정의합니다.
+//
+// 사용 예시:
+//
+//	var users = pgtestkit.DefineFactory("users",
+//		pgtestkit.WithDefaults(User{Role: "member"}),
+//		pgtestkit.WithSequence("email", "user%d@example.com"))
+//
+//	user := users.Create(t, db, map[string]interface{}{"role": "admin"})
+func DefineFactory(table string, opts ...FactoryOption) *Factory {
+	f := NewFactory(table, opts...)
+	factories.Store(table, f)
+	return f
+}
+
+// NewFactory 등록하지 않은 팩토리를 만듭니다. 부모 행을 만들 때는 사용되지 않습니다.
+func NewFactory(table string, opts ...FactoryOption) *Factory {
+	f := &Factory{
+		table:        table,
+		defaults:     make(map[string]interface{}),
+		sequences:    make(map[string]func(n int) interface{}),
+		associations: make(map[string]factoryAssociation),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(f)
+		}
+	}
+	return f
+}
+
+// Create table에 행 하나를 넣고 반환합니다. 등록된 팩토리가 없으면 필수 컬럼만 자동으로 채웁니다.
+// overrides는 nil, map[string]interface{} 또는 구조체(포인터)입니다. 실패하면 t.Fatalf로 테스트를 중단합니다.
+func Create(t testing.TB, db *sql.DB, table string, overrides interface{}) Row {
+	t.Helper()
+	return CreateMany(t, db, table, 1, overrides)[0]
+}
+
+// CreateMany table에 행 n개를 한 트랜잭션으로 넣고 반환합니다.
+func CreateMany(t testing.TB, db *sql.DB, table string, n int, overrides interface{}) []Row {
+	t.Helper()
+	f, ok := lookupFactory(table)
+	if !ok {
+		f = implicitFactory(table)
+	}
+	return f.CreateMany(t, db, n, overrides)
+}
+
+// Create 행 하나를 넣고 반환합니다. 실패하면 t.Fatalf로 테스트를 중단합니다.
+func (f *Factory) Create(t testing.TB, db *sql.DB, overrides interface{}) Row {
+	t.Helper()
+	return f.CreateMany(t, db, 1, overrides)[0]
+}
+
+// CreateMany 행 n개를 한 트랜잭션으로 넣고 반환합니다. 실패하면 t.Fatalf로 테스트를 중단합니다.
+func (f *Factory) CreateMany(t testing.TB, db *sql.DB, n int, overrides interface{}) []Row {
+	t.Helper()
+	rows, err := f.CreateManyContext(context.Background(), db, n, overrides)
+	if err != nil {
+		t.Fatalf("pgtestkit: %v", err)
+	}
+	return rows
+}
+
+// CreateManyContext 행 n개를 한 트랜잭션으로 넣고 반환합니다. 부모 행도 같은 트랜잭션에서 만들어집니다.
+func (f *Factory) CreateManyContext(ctx context.Context, db *sql.DB, n int, overrides interface{}) ([]Row, error) {
+	if f.err != nil {
+		return nil, fmt.Errorf("factory %s: %w", f.table, f.err)
+	}
+	values, err := factoryValues(overrides)
+	if err != nil {
+		return nil, fmt.Errorf("invalid overrides for %s: %w", f.table, err)
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	b := &factoryBuilder{tx: tx, tables: make(map[string]*factoryTable), creating: make(map[string]bool)}
+	rows := make([]Row, 0, n)
+	for i := 0; i < n; i++ {
+		row, err := b.create(ctx, f, values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s row: %w", f.table, err)
+		}
+		rows = append(rows, row)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit %s rows: %w", f.table, err)
+	}
+
+	getLogger().Debug("Created factory rows", zap.String("table", f.table), zap.Int("rows", n), zap.Int("parents", b.parents))
+	return rows, nil
+}
+
+// lookupFactory 등록된 팩토리를 names 순서로 찾습니다.
+func lookupFactory(names ...string) (*Factory, bool) {
+	for _, name := range names {
+		if f, ok := factories.Load(name); ok {
+			return f.(*Factory), true
+		}
+	}
+	return nil, false
+}
+
+// 팩토리를 정의하지 않은 테이블의 팩토리 (순번을 이어가기 위해 보관)
+var implicitFactories sync.Map
+
+// implicitFactory 옵션이 없는 테이블의 팩토리를 반환합니다.
+func implicitFactory(table string) *Factory {
+	f, _ := implicitFactories.LoadOrStore(table, NewFactory(table))
+	return f.(*Factory)
+}
+
+// factoryBuilder 트랜잭션 하나에서 행과 부모 행을 만듭니다.
+type factoryBuilder struct {
+	tx       *sql.Tx
+	tables   map[string]*factoryTable // describeFactoryTable 결과 (테이블 이름별)
+	creating map[string]bool          // 외래 키 순환을 찾기 위해 만드는 중인 테이블
+	parents  int
+}
+
+// factoryTable 팩토리가 사용하는 테이블의 컬럼 정보입니다.
+type factoryTable struct {
+	*tableInfo
+	order   []factoryColumn
+	foreign map[string][2]string // 단일 컬럼 외래 키: 컬럼 -> (부모 테이블, 참조 컬럼)
+}
+
+// factoryColumn information_schema.columns에서 읽은 컬럼 정보입니다.
+type factoryColumn struct {
+	name       string
+	dataType   string
+	required   bool // 값을 주지 않으면 INSERT가 실패하는 컬럼
+	maxLength  int
+	udtSchema  string
+	udtName    string
+	enumLabels []string
+}
+
+// create 행 하나를 넣습니다.
+func (b *factoryBuilder) create(ctx context.Context, f *Factory, overrides map[string]interface{}) (Row, error) {
+	table, err := b.describe(ctx, f.table)
+	if err != nil {
+		return nil, err
+	}
+	key := table.table.String()
+	if b.creating[key] {
+		return nil, fmt.Errorf("required foreign keys of %s refer back to it; set the column in the overrides", key)
+	}
+	b.creating[key] = true
+	defer delete(b.creating, key)
+
+	n := int(f.counter.Add(1))
+	values := make(map[string]interface{}, len(table.order))
+	for name, value := range overrides {
+		values[name] = value
+	}
+	for name, value := range f.defaults {
+		if _, ok := values[name]; !ok {
+			values[name] = value
+		}
+	}
+	for name, fn := range f.sequences {
+		if _, ok := values[name]; !ok {
+			values[name] = fn(n)
+		}
+	}
+	for name, assoc := range f.associations {
+		if _, ok := values[name]; ok {
+			continue
+		}
+		value, err := b.createParent(ctx, table, name, assoc)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = value
+	}
+
+	for _, column := range table.order {
+		if _, ok := values[column.name]; ok || !column.required {
+			continue
+		}
+		if ref, ok := table.foreign[column.name]; ok {
+			value, err := b.createParent(ctx, table, column.name, factoryAssociation{table: ref[0]})
+			if err != nil {
+				return nil, err
+			}
+			values[column.name] = value
+			continue
+		}
+		value, err := generateColumnValue(column, n)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", column.name, err)
+		}
+		values[column.name] = value
+	}
+
+	return b.insert(ctx, table, values)
+}
+
+// createParent assoc의 부모 행을 만들고 column에 넣을 값을 반환합니다.
+func (b *factoryBuilder) createParent(ctx context.Context, table *factoryTable, column string, assoc factoryAssociation) (interface{}, error) {
+	parent, ok := lookupFactory(assoc.table)
+	if !ok {
+		parentTable, err := b.describe(ctx, assoc.table)
+		if err != nil {
+			return nil, fmt.Errorf("association %s: %w", column, err)
+		}
+		name := parentTable.table.String()
+		if parent, ok = lookupFactory(name, parentTable.table.name); !ok {
+			parent = implicitFactory(name)
+		}
+	}
+	overrides, err := factoryValues(assoc.overrides)
+	if err != nil {
+		return nil, fmt.Errorf("invalid overrides for association %s: %w", column, err)
+	}
+
+	row, err := b.create(ctx, parent, overrides)
+	if err != nil {
+		return nil, fmt.Errorf("association %s: %w", column, err)
+	}
+	b.parents++
+
+	refColumn := ""
+	if ref, ok := table.foreign[column]; ok {
+		refColumn = ref[1]
+	} else {
+		parentTable, err := b.describe(ctx, parent.table)
+		if err != nil {
+			return nil, err
+		}
+		refColumn = parentTable.pk
+	}
+	value, ok := row[refColumn]
+	if !ok {
+		return nil, fmt.Errorf("association %s: cannot tell which column of %s it refers to", column, parent.table)
+	}
+	return value, nil
+}
+
+// insert 행을 넣고 RETURNING *으로 읽은 값을 반환합니다.
+func (b *factoryBuilder) insert(ctx context.Context, table *factoryTable, values map[string]interface{}) (Row, error) {
+	columns := make([]string, 0, len(values))
+	placeholders := make([]string, 0, len(values))
+	args := make([]interface{}, 0, len(values))
+	// 쿼리가 결정적이도록 테이블에 정의된 순서로 컬럼을 나열
+	for _, name := range table.tableInfo.order {
+		value, ok := values[name]
+		if !ok {
+			continue
+		}
+		arg, err := columnArg(value, table.columns[name])
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", name, err)
+		}
+		args = append(args, arg)
+		columns = append(columns, quoteIdentifier(name))
+		placeholders = append(placeholders, fmt.Sprintf("$%d::%s", len(args), table.columns[name].typ))
+	}
+	for name := range values {
+		if _, ok := table.columns[name]; !ok {
+			return nil, fmt.Errorf("column %s does not exist in %s", name, table.table)
+		}
+	}
+
+	query := "INSERT INTO " + table.table.quoted()
+	if len(columns) == 0 {
+		query += " DEFAULT VALUES"
+	} else {
+		query += fmt.Sprintf(" (%s) OVERRIDING SYSTEM VALUE VALUES (%s)", strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+	}
+
+	rows, err := b.tx.QueryContext(ctx, query+" RETURNING *", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	names, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("insert into %s returned no row", table.table)
+	}
+	dest := make([]interface{}, len(names))
+	ptrs := make([]interface{}, len(names))
+	for i := range dest {
+		ptrs[i] = &dest[i]
+	}
+	if err := rows.Scan(ptrs...); err != nil {
+		return nil, err
+	}
+	row := make(Row, len(names))
+	for i, name := range names {
+		row[name] = dest[i]
+	}
+	return row, rows.Close()
+}
+
+// describe 테이블의 컬럼 타입, NOT NULL 제약과 외래 키를 읽습니다. 결과는 트랜잭션 안에서 재사용합니다.
+func (b *factoryBuilder) describe(ctx context.Context, name string) (*factoryTable, error) {
+	if table, ok := b.tables[name]; ok {
+		return table, nil
+	}
+	info, err := describeTable(ctx, b.tx, name)
+	if err != nil {
+		return nil, err
+	}
+	table := &factoryTable{tableInfo: info, foreign: make(map[string][2]string)}
+
+	rows, err := queryStrings(ctx, b.tx, `
+		SELECT column_name, data_type,
+			(is_nullable = 'NO' AND column_default IS NULL AND is_identity = 'NO' AND is_generated = 'NEVER')::text,
+			coalesce(character_maximum_length, 0)::text, udt_schema, udt_name
+		FROM information_schema.columns
+		WHERE table_schema = $1 AND table_name = $2
+		ORDER BY ordinal_position`, info.table.schema, info.table.name)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		column := factoryColumn{
+			name:      row[0],
+			dataType:  row[1],
+			required:  row[2] == "true",
+			udtSchema: row[4],
+			udtName:   row[5],
+		}
+		_, _ = fmt.Sscan(row[3], &column.maxLength)
+		if column.required && column.dataType == "USER-DEFINED" {
+			labels, err := queryStrings(ctx, b.tx, `
+				SELECT e.enumlabel
+				FROM pg_enum e
+				JOIN pg_type t ON t.oid = e.enumtypid
+				JOIN pg_namespace n ON n.oid = t.typnamespace
+				WHERE n.nspname = $1 AND t.typname = $2
+				ORDER BY e.enumsortorder`, column.udtSchema, column.udtName)
+			if err != nil {
+				return nil, err
+			}
+			for _, label := range labels {
+				column.enumLabels = append(column.enumLabels, label[0])
+			}
+		}
+		table.order = append(table.order, column)
+	}
+
+	rows, err = queryStrings(ctx, b.tx, `
+		SELECT a.attname, rn.nspname || '.' || rc.relname, ra.attname
+		FROM pg_constraint c
+		JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = c.conkey[1]
+		JOIN pg_class rc ON rc.oid = c.confrelid
+		JOIN pg_namespace rn ON rn.oid = rc.relnamespace
+		JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = c.confkey[1]
+		WHERE c.conrelid = $1::regclass AND c.contype = 'f' AND cardinality(c.conkey) = 1`, info.table.quoted())
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		table.foreign[row[0]] = [2]string{row[1], row[2]}
+	}
+
+	b.tables[name] = table
+	b.tables[info.table.String()] = table
+	return table, nil
+}
+
+// generateColumnValue 값을 주지 않은 필수 컬럼에 넣을 값을 컬럼 타입에 맞게 만듭니다.
+// 문자열과 숫자는 순번 n을 사용하므로 UNIQUE 제약이 있어도 겹치지 않습니다.
+func generateColumnValue(column factoryColumn, n int) (interface{}, error) {
+	switch column.dataType {
+	case "smallint", "integer", "bigint", "numeric", "real", "double precision", "money":
+		return n, nil
+	case "boolean":
+		return false, nil
+	case "text", "character varying", "character":
+		s := fmt.Sprintf("%s-%d", column.name, n)
+		if column.maxLength > 0 && len(s) > column.maxLength {
+			// 고유한 순번이 남도록 앞부분을 자름
+			s = s[len(s)-column.maxLength:]
+		}
+		return s, nil
+	case "uuid":
+		return randomUUID()
+	case "date":
+		return time.Now().UTC().Format(time.DateOnly), nil
+	case "timestamp without time zone", "timestamp with time zone":
+		return time.Now().UTC(), nil
+	case "time without time zone", "time with time zone", "interval":
+		return "00:00:00", nil
+	case "json", "jsonb":
+		return "{}", nil
+	case "bytea":
+		return []byte{}, nil
+	case "ARRAY":
+		return "{}", nil
+	case "inet", "cidr":
+		return "127.0.0.1", nil
+	case "USER-DEFINED":
+		if len(column.enumLabels) > 0 {
+			return column.enumLabels[0], nil
+		}
+	}
+	return nil, fmt.Errorf("cannot generate a value of type %s; set it with WithDefaults or in the overrides", column.udtName)
+}
+
+// randomUUID 무작위 UUID(버전 4)를 만듭니다.
+func randomUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate uuid: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// factoryValues 팩토리에 전달한 값을 컬럼 이름별 맵으로 바꿉니다.
+// nil, map[string]interface{}, Row, 구조체와 구조체 포인터를 받습니다.
+func factoryValues(values interface{}) (map[string]interface{}, error) {
+	switch v := values.(type) {
+	case nil:
+		return nil, nil
+	case map[string]interface{}:
+		return v, nil
+	case Row:
+		return v, nil
+	}
+
+	rv := reflect.ValueOf(values)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a map or struct, got %T", values)
+	}
+
+	m := make(map[string]interface{})
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get("db")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = snakeCase(field.Name)
+		}
+		// zero 값은 지정하지 않은 것으로 보고 기본값과 자동 생성에 맡김
+		if value := rv.Field(i); !value.IsZero() {
+			m[name] = value.Interface()
+		}
+	}
+	return m, nil
+}
+
+// snakeCase Go 필드 이름을 컬럼 이름으로 바꿉니다. 예: UserID -> user_id, CreatedAt -> created_at
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,71 @@
+package pgtestkit
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Name":      "name",
+		"CreatedAt": "created_at",
+		"UserID":    "user_id",
+		"ID":        "id",
+		"HTTPCode":  "http_code",
+		"Address2":  "address2",
+		"V2Name":    "v2_name",
+	}
+	for in, want := range tests {
+		if got := snakeCase(in); got != want {
+			t.Errorf("snakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFactoryValues(t *testing.T) {
+	type user struct {
+		ID       int
+		Name     string
+		Email    string `db:"email_address"`
+		Internal string `db:"-"`
+		Active   bool
+		secret   string
+	}
+	got, err := factoryValues(&user{Name: "Alice", Email: "a@example.com", Internal: "x", secret: "y"})
+	if err != nil {
+		t.Fatalf("factoryValues() error: %v", err)
+	}
+	want := map[string]interface{}{"name": "Alice", "email_address": "a@example.com"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("factoryValues() = %v, want %v", got, want)
+	}
+
+	if got, err := factoryValues(nil); err != nil || got != nil {
+		t.Errorf("factoryValues(nil) = %v, %v", got, err)
+	}
+	if _, err := factoryValues(42); err == nil {
+		t.Error("Expected error for non-struct values")
+	}
+}
+
+func TestGenerateColumnValue(t *testing.T) {
+	value, err := generateColumnValue(factoryColumn{name: "username", dataType: "character varying", maxLength: 8}, 123)
+	if err != nil || value != "name-123" {
+		t.Errorf("Expected truncated value to keep the sequence, got %v, %v", value, err)
+	}
+
+	value, err = generateColumnValue(factoryColumn{name: "status", dataType: "USER-DEFINED", enumLabels: []string{"active", "banned"}}, 1)
+	if err != nil || value != "active" {
+		t.Errorf("Expected first enum label, got %v, %v", value, err)
+	}
+
+	value, err = generateColumnValue(factoryColumn{name: "id", dataType: "uuid"}, 1)
+	if err != nil || !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(value.(string)) {
+		t.Errorf("Expected random uuid, got %v, %v", value, err)
+	}
+
+	if _, err := generateColumnValue(factoryColumn{name: "location", dataType: "USER-DEFINED", udtName: "geometry"}, 1); err == nil {
+		t.Error("Expected error for unsupported type")
+	}
+}
@@ -0,0 +1,42 @@
+//go:build !windows
+
+package pgtestkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"syscall"
+	"time"
+)
+
+// lockFile 여러 프로세스 사이에서 배타적으로 사용할 파일 잠금을 획득합니다.
+// 잠금을 획득할 때까지 대기하며, 컨텍스트가 끝나면 에러를 반환합니다.
+// 반환된 함수를 호출하면 잠금이 해제됩니다.
+func lockFile(ctx context.Context, path string) (func(), error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file %s: %w", path, err)
+	}
+
+	for {
+		err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) && !errors.Is(err, syscall.EINTR) {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if err := sleepContext(ctx, 50*time.Millisecond); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("interrupted while waiting for lock %s: %w", path, err)
+		}
+	}
+
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,40 @@
+//go:build windows
+
+package pgtestkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// staleLockAge 비정상 종료로 남은 잠금 파일로 간주하는 나이입니다.
+const staleLockAge = 10 * time.Minute
+
+// lockFile 여러 프로세스 사이에서 배타적으로 사용할 파일 잠금을 획득합니다.
+// Windows에서는 잠금 파일을 배타적으로 생성하는 방식을 사용하며, 오래된 잠금 파일은 제거합니다.
+// 반환된 함수를 호출하면 잠금이 해제됩니다.
+func lockFile(ctx context.Context, path string) (func(), error) {
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			f.Close()
+			return func() {
+				_ = os.Remove(path)
+			}, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock file %s: %w", path, err)
+		}
+
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			_ = os.Remove(path)
+			continue
+		}
+		if err := sleepContext(ctx, 50*time.Millisecond); err != nil {
+			return nil, fmt.Errorf("interrupted while waiting for lock %s: %w", path, err)
+		}
+	}
+}
@@ -0,0 +1,590 @@
+package pgtestkit
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"reflect"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// FixtureRefPrefix 다른 픽스처 행의 기본 키를 참조하는 값의 접두사입니다.
+	// "$ref:users.alice"는 users 픽스처에서 alice로 표시한 행의 기본 키로 바뀝니다.
+	FixtureRefPrefix = "$ref:"
+
+	// FixtureLabelColumn 목록 형식의 YAML/JSON과 CSV에서 행의 이름을 지정하는 컬럼입니다.
+	FixtureLabelColumn = "_label"
+)
+
+// fixtureFile 픽스처 파일 하나의 내용입니다.
+type fixtureFile struct {
+	name  string // 파일 경로
+	table string // 파일 이름에서 확장자를 뺀 테이블 이름 (schema.table 가능)
+	rows  []fixtureRow
+}
+
+// fixtureRow 픽스처의 행 하나입니다.
+type fixtureRow struct {
+	label   string
+	columns []string
+	values  []interface{} // nil, 문자열(스칼라) 또는 []interface{}, map[string]interface{}
+}
+
+// tableInfo 픽스처를 적재하거나 검증할 테이블의 카탈로그 정보입니다.
+type tableInfo struct {
+	table   resetTable
+	columns map[string]columnInfo
+	order   []string // 테이블에 정의된 순서의 컬럼 이름
+	pk      string   // 단일 컬럼 기본 키 (없거나 복합 키이면 빈 문자열)
+}
+
+// columnInfo 테이블 컬럼의 타입 정보입니다.
+type columnInfo struct {
+	typ   string
+	array bool
+}
+
+// LoadFixtures fsys의 픽스처 파일에서 행을 읽어 테이블에 넣습니다.
+// 자세한 동작은 LoadFixturesContext를 참고하세요.
+func LoadFixtures(db *sql.DB, fsys fs.FS, files ...string) error {
+	return LoadFixturesContext(context.Background(), db, fsys, files...)
+}
+
+// LoadFixturesContext fsys의 픽스처 파일(.yml, .yaml, .json, .csv)에서 행을 읽어 한 트랜잭션으로 테이블에 넣습니다.
+// files를 생략하면 fsys 최상위 디렉토리의 모든 픽스처 파일을 적재합니다.
+// 파일 이름에서 확장자를 뺀 부분이 테이블 이름이며(users.yml, audit.events.csv), 테이블은 pg_constraint의
+// 외래 키를 따라 참조되는 테이블부터 적재됩니다.
+//
+// YAML/JSON 파일은 이름을 키로 하는 행의 맵이나 행의 목록이고, CSV 파일은 첫 줄이 컬럼 이름입니다.
+// 목록과 CSV에서는 _label 컬럼으로 행의 이름을 지정할 수 있으며, "$ref:table.label" 값은 해당 행의 기본 키로 바뀝니다.
+// 값은 컬럼 타입으로 변환되므로 timestamp, bytea("\x..."), jsonb(중첩된 맵이나 목록), 배열(목록) 컬럼도 그대로 쓸 수 있습니다.
+// CSV의 빈 값은 NULL입니다.
+//
+// 적재가 끝나면 테이블이 소유한 시퀀스(serial, identity)를 넣은 값의 최댓값 이후로 옮겨, 이후의 INSERT가 충돌하지 않게 합니다.
+//
+// 사용 예시:
+//
+//	# testdata/fixtures/users.yml
+//	alice:
+//	  name: Alice
+//	  tags: [admin, staff]
+//	  settings: {theme: dark}
+//
+//	# testdata/fixtures/posts.yml
+//	- author_id: $ref:users.alice
+//	  created_at: 2024-01-02T03:04:05Z
+//
+//	err := pgtestkit.LoadFixtures(db, os.DirFS("testdata/fixtures"))
+func LoadFixturesContext(ctx context.Context, db *sql.DB, fsys fs.FS, files ...string) error {
+	if db == nil {
+		return fmt.Errorf("database must not be nil")
+	}
+	if fsys == nil {
+		return fmt.Errorf("fixtures file system must not be nil")
+	}
+
+	if len(files) == 0 {
+		entries, err := fs.ReadDir(fsys, ".")
+		if err != nil {
+			return fmt.Errorf("failed to read fixtures directory: %w", err)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && isFixtureFile(entry.Name()) {
+				files = append(files, entry.Name())
+			}
+		}
+	}
+
+	fixtures := make([]fixtureFile, 0, len(files))
+	for _, name := range files {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return fmt.Errorf("failed to read fixture %s: %w", name, err)
+		}
+		fixture, err := parseFixture(name, data)
+		if err != nil {
+			return err
+		}
+		fixtures = append(fixtures, fixture)
+	}
+
+	logger := getLogger()
+	logger.Info("Loading fixtures", zap.Int("files", len(fixtures)))
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin fixtures transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tables := make(map[string]*tableInfo, len(fixtures))
+	order := make([]resetTable, 0, len(fixtures))
+	byTable := make(map[resetTable][]fixtureFile, len(fixtures))
+	for _, fixture := range fixtures {
+		table, ok := tables[fixture.table]
+		if !ok {
+			table, err = describeTable(ctx, tx, fixture.table)
+			if err != nil {
+				return fmt.Errorf("fixture %s: %w", fixture.name, err)
+			}
+			tables[fixture.table] = table
+		}
+		if _, ok := byTable[table.table]; !ok {
+			order = append(order, table.table)
+		}
+		byTable[table.table] = append(byTable[table.table], fixture)
+	}
+
+	refs, err := listForeignKeys(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	// 행의 이름("table.label")별 기본 키
+	keys := make(map[string]string)
+	inserted := 0
+	for _, ref := range insertOrder(order, refs) {
+		for _, fixture := range byTable[ref] {
+			table := tables[fixture.table]
+			for i, row := range fixture.rows {
+				n, err := insertFixtureRow(ctx, tx, table, fixture.table, row, keys)
+				if err != nil {
+					where := fmt.Sprintf("row %d", i+1)
+					if row.label != "" {
+						where = "row " + row.label
+					}
+					return fmt.Errorf("fixture %s %s: %w", fixture.name, where, err)
+				}
+				inserted += n
+			}
+		}
+		if err := advanceOwnedSequences(ctx, tx, tables[byTable[ref][0].table]); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit fixtures: %w", err)
+	}
+	logger.Info("Fixtures loaded", zap.Int("tables", len(order)), zap.Int("rows", inserted))
+	return nil
+}
+
+// isFixtureFile 픽스처로 읽을 수 있는 확장자인지 확인합니다.
+func isFixtureFile(name string) bool {
+	switch path.Ext(name) {
+	case ".yml", ".yaml", ".json", ".csv":
+		return true
+	}
+	return false
+}
+
+// parseFixture 확장자에 맞게 픽스처 파일을 해석합니다. JSON은 YAML의 부분 집합이므로 YAML로 읽습니다.
+func parseFixture(name string, data []byte) (fixtureFile, error) {
+	ext := path.Ext(name)
+	fixture := fixtureFile{name: name, table: strings.TrimSuffix(path.Base(name), ext)}
+
+	var err error
+	switch ext {
+	case ".yml", ".yaml", ".json":
+		fixture.rows, err = parseYAMLFixture(data)
+	case ".csv":
+		fixture.rows, err = parseCSVFixture(data)
+	default:
+		err = fmt.Errorf("unsupported fixture format %q", ext)
+	}
+	if err != nil {
+		return fixtureFile{}, fmt.Errorf("failed to parse fixture %s: %w", name, err)
+	}
+	return fixture, nil
+}
+
+// parseYAMLFixture 이름을 키로 하는 행의 맵이나 행의 목록을 읽습니다. 파일에 적힌 순서를 유지합니다.
+func parseYAMLFixture(data []byte) ([]fixtureRow, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+
+	var rows []fixtureRow
+	root := doc.Content[0]
+	switch root.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			row, err := parseYAMLRow(root.Content[i+1])
+			if err != nil {
+				return nil, fmt.Errorf("row %s: %w", root.Content[i].Value, err)
+			}
+			row.label = root.Content[i].Value
+			rows = append(rows, row)
+		}
+	case yaml.SequenceNode:
+		for i, node := range root.Content {
+			row, err := parseYAMLRow(node)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: %w", i+1, err)
+			}
+			rows = append(rows, row)
+		}
+	default:
+		return nil, fmt.Errorf("line %d: expected a mapping of labeled rows or a list of rows", root.Line)
+	}
+	return rows, nil
+}
+
+// parseYAMLRow 컬럼 이름을 키로 하는 맵을 행으로 읽습니다.
+func parseYAMLRow(node *yaml.Node) (fixtureRow, error) {
+	if node.Kind != yaml.MappingNode {
+		return fixtureRow{}, fmt.Errorf("line %d: expected a mapping of column values", node.Line)
+	}
+
+	var row fixtureRow
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		column, value := node.Content[i].Value, node.Content[i+1]
+
+		var v interface{}
+		switch {
+		case value.Kind == yaml.ScalarNode && value.Tag == "!!null":
+			v = nil
+		case value.Kind == yaml.ScalarNode:
+			// 타입 변환은 PostgreSQL에 맡기기 위해 적힌 그대로 사용
+			v = value.Value
+		default:
+			if err := value.Decode(&v); err != nil {
+				return fixtureRow{}, fmt.Errorf("column %s: %w", column, err)
+			}
+		}
+
+		if column == FixtureLabelColumn {
+			label, ok := v.(string)
+			if !ok {
+				return fixtureRow{}, fmt.Errorf("line %d: %s must be a string", value.Line, FixtureLabelColumn)
+			}
+			row.label = label
+			continue
+		}
+		row.columns = append(row.columns, column)
+		row.values = append(row.values, v)
+	}
+	return row, nil
+}
+
+// parseCSVFixture 첫 줄을 컬럼 이름으로 하는 CSV를 읽습니다. 빈 값은 NULL입니다.
+func parseCSVFixture(data []byte) ([]fixtureRow, error) {
+	r := csv.NewReader(bytes.NewReader(data))
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []fixtureRow
+	for {
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var row fixtureRow
+		for i, column := range header {
+			if column == FixtureLabelColumn {
+				row.label = record[i]
+				continue
+			}
+			var v interface{}
+			if record[i] != "" {
+				v = record[i]
+			}
+			row.columns = append(row.columns, column)
+			row.values = append(row.values, v)
+		}
+		rows = append(rows, row)
+	}
+}
+
+// qualifiedTableName "schema.table" 또는 search_path에서 찾을 "table"을 이스케이프합니다.
+func qualifiedTableName(name string) string {
+	if schema, table, ok := strings.Cut(name, "."); ok {
+		return quoteIdentifier(schema) + "." + quoteIdentifier(table)
+	}
+	return quoteIdentifier(name)
+}
+
+// describeTable 테이블의 컬럼 타입과 단일 컬럼 기본 키를 읽습니다.
+func describeTable(ctx context.Context, tx sqlQueryer, name string) (*tableInfo, error) {
+	qualified := qualifiedTableName(name)
+
+	rows, err := queryStrings(ctx, tx, `
+		SELECT n.nspname, c.relname
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.oid = to_regclass($1)`, qualified)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("table %s does not exist", name)
+	}
+	table := &tableInfo{
+		table:   resetTable{schema: rows[0][0], name: rows[0][1]},
+		columns: make(map[string]columnInfo),
+	}
+
+	rows, err = queryStrings(ctx, tx, `
+		SELECT a.attname, format_type(a.atttypid, a.atttypmod), t.typcategory
+		FROM pg_attribute a
+		JOIN pg_type t ON t.oid = a.atttypid
+		WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum`, qualified)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		table.columns[row[0]] = columnInfo{typ: row[1], array: row[2] == "A"}
+		table.order = append(table.order, row[0])
+	}
+
+	rows, err = queryStrings(ctx, tx, `
+		SELECT a.attname
+		FROM pg_index i
+		JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+		WHERE i.indrelid = $1::regclass AND i.indisprimary`, qualified)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 1 {
+		table.pk = rows[0][0]
+	}
+	return table, nil
+}
+
+// insertOrder 참조되는 테이블이 참조하는 테이블보다 먼저 오도록 tables를 정렬합니다.
+// 순서가 정해지지 않는 테이블은 주어진 순서를 유지하며, 순환 참조에 포함된 테이블은 마지막에 둡니다.
+func insertOrder(tables []resetTable, refs [][2]resetTable) []resetTable {
+	included := make(map[resetTable]bool, len(tables))
+	for _, t := range tables {
+		included[t] = true
+	}
+	dependsOn := make(map[resetTable][]resetTable)
+	for _, ref := range refs {
+		from, to := ref[0], ref[1]
+		if from != to && included[from] && included[to] {
+			dependsOn[from] = append(dependsOn[from], to)
+		}
+	}
+
+	order := make([]resetTable, 0, len(tables))
+	done := make(map[resetTable]bool, len(tables))
+	for len(order) < len(tables) {
+		progressed := false
+		for _, t := range tables {
+			if done[t] {
+				continue
+			}
+			ready := true
+			for _, dep := range dependsOn[t] {
+				if !done[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[t] = true
+				order = append(order, t)
+				progressed = true
+				break
+			}
+		}
+		if !progressed {
+			for _, t := range tables {
+				if !done[t] {
+					order = append(order, t)
+				}
+			}
+			break
+		}
+	}
+	return order
+}
+
+// insertFixtureRow 행 하나를 넣고 이름이 있으면 기본 키를 keys에 기록합니다. 넣은 행 수를 반환합니다.
+func insertFixtureRow(ctx context.Context, tx *sql.Tx, table *tableInfo, fixtureName string, row fixtureRow, keys map[string]string) (int, error) {
+	columns := make([]string, len(row.columns))
+	placeholders := make([]string, len(row.columns))
+	args := make([]interface{}, len(row.columns))
+	for i, name := range row.columns {
+		column, ok := table.columns[name]
+		if !ok {
+			return 0, fmt.Errorf("column %s does not exist in %s", name, table.table)
+		}
+		arg, err := fixtureArg(row.values[i], column, keys)
+		if err != nil {
+			return 0, fmt.Errorf("column %s: %w", name, err)
+		}
+		columns[i] = quoteIdentifier(name)
+		placeholders[i] = fmt.Sprintf("$%d::%s", i+1, column.typ)
+		args[i] = arg
+	}
+
+	query := "INSERT INTO " + table.table.quoted()
+	if len(columns) == 0 {
+		query += " DEFAULT VALUES"
+	} else {
+		// identity 컬럼(GENERATED ALWAYS)에도 픽스처의 값을 넣음
+		query += fmt.Sprintf(" (%s) OVERRIDING SYSTEM VALUE VALUES (%s)", strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+	}
+
+	if row.label == "" || table.pk == "" {
+		if row.label != "" {
+			getLogger().Debug("Fixture row label cannot be referenced without a single-column primary key",
+				zap.String("table", table.table.String()), zap.String("label", row.label))
+		}
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return 0, err
+		}
+		return 1, nil
+	}
+
+	var key string
+	if err := tx.QueryRowContext(ctx, query+" RETURNING "+quoteIdentifier(table.pk)+"::text", args...).Scan(&key); err != nil {
+		return 0, err
+	}
+	keys[fixtureName+"."+row.label] = key
+	return 1, nil
+}
+
+// fixtureArg 픽스처 값을 컬럼 타입으로 변환할 수 있는 텍스트 인자로 바꿉니다.
+func fixtureArg(value interface{}, column columnInfo, keys map[string]string) (interface{}, error) {
+	if v, ok := value.(string); ok {
+		if ref, ok := strings.CutPrefix(v, FixtureRefPrefix); ok {
+			key, ok := keys[ref]
+			if !ok {
+				return nil, fmt.Errorf("reference %s is not a labeled row loaded before this one", ref)
+			}
+			return key, nil
+		}
+	}
+	return columnArg(value, column)
+}
+
+// columnArg Go 값을 PostgreSQL이 컬럼 타입으로 변환할 수 있는 텍스트로 바꿉니다. nil은 NULL입니다.
+// 배열 컬럼의 슬라이스는 배열 리터럴이 되고, 그 밖의 맵과 슬라이스는 json/jsonb 값으로 사용됩니다.
+func columnArg(value interface{}, column columnInfo) (interface{}, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return v, nil
+	case []byte:
+		return `\x` + hex.EncodeToString(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(v), nil
+	}
+
+	if rv := reflect.ValueOf(value); column.array && (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array) {
+		elements := make([]interface{}, rv.Len())
+		for i := range elements {
+			elements[i] = rv.Index(i).Interface()
+		}
+		return arrayLiteral(elements)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// arrayLiteral 목록을 PostgreSQL 배열 리터럴로 바꿉니다. 중첩된 목록은 다차원 배열이 됩니다.
+func arrayLiteral(values []interface{}) (string, error) {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, value := range values {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		var element string
+		switch v := value.(type) {
+		case nil:
+			b.WriteString("NULL")
+			continue
+		case []interface{}:
+			nested, err := arrayLiteral(v)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(nested)
+			continue
+		case string:
+			element = v
+		case time.Time:
+			element = v.Format(time.RFC3339Nano)
+		case bool, int, int64, uint64, float64:
+			element = fmt.Sprint(v)
+		default:
+			data, err := json.Marshal(v)
+			if err != nil {
+				return "", err
+			}
+			element = string(data)
+		}
+		b.WriteByte('"')
+		b.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(element))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+// advanceOwnedSequences 테이블이 소유한 시퀀스가 컬럼의 최댓값 이후의 값을 내도록 옮깁니다.
+// 이미 더 큰 값을 낼 시퀀스는 그대로 둡니다.
+func advanceOwnedSequences(ctx context.Context, tx *sql.Tx, table *tableInfo) error {
+	rows, err := queryStrings(ctx, tx, `
+		SELECT s.oid::regclass::text, a.attname
+		FROM pg_depend d
+		JOIN pg_class s ON s.oid = d.objid AND s.relkind = 'S'
+		JOIN pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+		WHERE d.classid = 'pg_class'::regclass
+			AND d.refclassid = 'pg_class'::regclass
+			AND d.deptype IN ('a', 'i')
+			AND d.refobjid = $1::regclass`, table.table.quoted())
+	if err != nil {
+		return fmt.Errorf("failed to list owned sequences: %w", err)
+	}
+
+	for _, row := range rows {
+		// regclass의 text 표현은 이미 이스케이프되어 있음
+		seq, column := row[0], row[1]
+		query := fmt.Sprintf(`SELECT setval($1::regclass, m) FROM (SELECT max(%s)::bigint AS m FROM %s) x
+			WHERE m IS NOT NULL AND m >= (SELECT CASE WHEN is_called THEN last_value + 1 ELSE last_value END FROM %s)`,
+			quoteIdentifier(column), table.table.quoted(), seq)
+		if _, err := tx.ExecContext(ctx, query, seq); err != nil {
+			return fmt.Errorf("failed to advance sequence %s: %w", seq, err)
+		}
+	}
+	return nil
+}
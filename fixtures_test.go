@@ -0,0 +1,87 @@
+package pgtestkit
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseFixture(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []fixtureRow
+	}{
+		{
+			name: "users.yml",
+			data: "alice:\n  name: Alice\n  tags: [a, b]\n  bio: null\nbob:\n  name: Bob\n",
+			want: []fixtureRow{
+				{label: "alice", columns: []string{"name", "tags", "bio"}, values: []interface{}{"Alice", []interface{}{"a", "b"}, nil}},
+				{label: "bob", columns: []string{"name"}, values: []interface{}{"Bob"}},
+			},
+		},
+		{
+			name: "posts.yaml",
+			data: "- _label: first\n  author_id: $ref:users.alice\n- title: untitled\n",
+			want: []fixtureRow{
+				{label: "first", columns: []string{"author_id"}, values: []interface{}{"$ref:users.alice"}},
+				{columns: []string{"title"}, values: []interface{}{"untitled"}},
+			},
+		},
+		{
+			name: "app.settings.json",
+			data: `[{"id": 1, "data": {"theme": "dark"}}]`,
+			want: []fixtureRow{
+				{columns: []string{"id", "data"}, values: []interface{}{"1", map[string]interface{}{"theme": "dark"}}},
+			},
+		},
+		{
+			name: "tags.csv",
+			data: "_label,name,note\nred,Red,\nblue,Blue,\"a, b\"\n",
+			want: []fixtureRow{
+				{label: "red", columns: []string{"name", "note"}, values: []interface{}{"Red", nil}},
+				{label: "blue", columns: []string{"name", "note"}, values: []interface{}{"Blue", "a, b"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		got, err := parseFixture(tt.name, []byte(tt.data))
+		if err != nil {
+			t.Errorf("parseFixture(%s) error = %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got.rows, tt.want) {
+			t.Errorf("parseFixture(%s) rows = %#v, want %#v", tt.name, got.rows, tt.want)
+		}
+	}
+
+	if got, _ := parseFixture("app.settings.json", []byte("[]")); got.table != "app.settings" {
+		t.Errorf("parseFixture() table = %q, want app.settings", got.table)
+	}
+	if _, err := parseFixture("users.yml", []byte("- just a string\n")); err == nil {
+		t.Error("parseFixture() expected error for a row that is not a mapping")
+	}
+}
+
+func TestArrayLiteral(t *testing.T) {
+	got, err := arrayLiteral([]interface{}{"a", `say "hi"`, nil, []interface{}{1, 2}})
+	if err != nil {
+		t.Fatalf("arrayLiteral() error = %v", err)
+	}
+	if want := `{"a","say \"hi\"",NULL,{"1","2"}}`; got != want {
+		t.Errorf("arrayLiteral() = %s, want %s", got, want)
+	}
+}
+
+func TestInsertOrder(t *testing.T) {
+	users := resetTable{"public", "users"}
+	posts := resetTable{"public", "posts"}
+	comments := resetTable{"public", "comments"}
+	tags := resetTable{"public", "tags"}
+
+	refs := [][2]resetTable{{comments, posts}, {posts, users}, {comments, users}, {users, users}}
+	got := insertOrder([]resetTable{comments, tags, posts, users}, refs)
+	want := []resetTable{tags, users, posts, comments}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("insertOrder() = %v, want %v", got, want)
+	}
+}
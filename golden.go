@@ -0,0 +1,100 @@
+package pgtestkit
+
+import (
+	"database/sql"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+const (
+	// UpdateGoldenFlag 골든 파일을 현재 결과로 다시 쓰는 go test 플래그 이름입니다 (go test -update).
+	UpdateGoldenFlag = "update"
+
+	// UpdateGoldenEnv 플래그 대신 골든 파일을 다시 쓰도록 지정하는 환경 변수 이름입니다.
+	UpdateGoldenEnv = "PGTESTKIT_UPDATE_GOLDEN"
+)
+
+// RegisterUpdateFlag 테스트 패키지가 -update 플래그를 정의하지 않았다면 정의합니다.
+// 패키지 초기화 시점에 정의하면 같은 이름의 플래그를 가진 테스트 패키지와 충돌하므로 자동으로 정의하지 않습니다.
+//
+// TestMainWrapper와 RunTests는 플래그를 파싱하는 m.Run 직전에 이 함수를 호출합니다. TestMain에서 먼저 flag.Parse를
+// 호출하거나 StartServer, NewServer로 서버를 시작하는 패키지는 flag.Parse 전에 직접 호출해야 go test -update를 사용할 수 있습니다.
+// 플래그를 정의할 수 없는 환경에서는 UpdateGoldenEnv 환경 변수를 사용하세요.
+//
+// 사용 예시:
+//
+//	func TestMain(m *testing.M) {
+//		pgtestkit.RegisterUpdateFlag()
+//		flag.Parse()
+//		// ...
+//	}
+func RegisterUpdateFlag() {
+	if flag.Lookup(UpdateGoldenFlag) == nil {
+		flag.Bool(UpdateGoldenFlag, false, "rewrite golden files with the current results")
+	}
+}
+
+// updateGolden 골든 파일을 다시 써야 하는지 확인합니다.
+func updateGolden() bool {
+	if envBool(UpdateGoldenEnv) {
+		return true
+	}
+	f := flag.Lookup(UpdateGoldenFlag)
+	if f == nil {
+		return false
+	}
+	update, err := strconv.ParseBool(f.Value.String())
+	return err == nil && update
+}
+
+// AssertSchemaGolden db의 SchemaSnapshot을 testdata 디렉토리의 골든 파일과 비교하고, 다르면 차이와 함께 테스트를 실패시킵니다.
+// PGTESTKIT_UPDATE_GOLDEN=1(또는 go test -update)로 실행하면 골든 파일을 현재 스냅샷으로 다시 씁니다.
+// 환경 변수는 서버를 시작하는 방법과 관계없이 동작합니다. -update 플래그는 TestMainWrapper, RunTests 또는
+// RegisterUpdateFlag가 정의하며, 테스트 패키지가 같은 이름의 플래그를 이미 정의했다면 그 값을 따릅니다.
+//
+// 사용 예시:
+//
+//	func TestSchema(t *testing.T) {
+//		dbClient, _ := pgtestkit.New(t, &SQLConnector{})
+//		pgtestkit.AssertSchemaGolden(t, dbClient.Client.(*sql.DB), "schema.golden")
+//	}
+func AssertSchemaGolden(t testing.TB, db *sql.DB, name string) {
+	t.Helper()
+
+	got, err := SchemaSnapshot(db)
+	if err != nil {
+		t.Fatalf("Failed to capture schema snapshot: %v", err)
+	}
+
+	path := filepath.Join("testdata", name)
+	if updateGolden() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("Failed to create golden file directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatalf("Failed to update golden file: %v", err)
+		}
+		t.Logf("Updated golden file %s", path)
+		return
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Golden file %s does not exist, run go test with -%s to create it", path, UpdateGoldenFlag)
+	}
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v", err)
+	}
+
+	// Windows 체크아웃의 줄바꿈 차이는 무시
+	want := strings.ReplaceAll(string(data), "\r\n", "\n")
+	if diff := diffLines(want, got); diff != "" {
+		t.Errorf("Schema does not match golden file %s (- golden, + actual), run go test with -%s to accept:\n%s",
+			path, UpdateGoldenFlag, diff)
+	}
+}
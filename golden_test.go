@@ -0,0 +1,32 @@
+package pgtestkit
+
+import (
+	"flag"
+	"testing"
+)
+
+func TestUpdateGolden(t *testing.T) {
+	// 이미 정의된 플래그가 있어도 다시 정의하지 않아야 함
+	RegisterUpdateFlag()
+	RegisterUpdateFlag()
+
+	// go test -update로 실행된 경우를 위해 원래 값을 복원
+	original := flag.Lookup(UpdateGoldenFlag).Value.String()
+	defer func() { _ = flag.Set(UpdateGoldenFlag, original) }()
+	t.Setenv(UpdateGoldenEnv, "")
+
+	for _, value := range []string{"false", "true"} {
+		if err := flag.Set(UpdateGoldenFlag, value); err != nil {
+			t.Fatalf("Failed to set flag: %v", err)
+		}
+		if got := updateGolden(); got != (value == "true") {
+			t.Errorf("updateGolden() with -update=%s = %v", value, got)
+		}
+	}
+
+	_ = flag.Set(UpdateGoldenFlag, "false")
+	t.Setenv(UpdateGoldenEnv, "1")
+	if !updateGolden() {
+		t.Errorf("Expected %s to enable golden file updates", UpdateGoldenEnv)
+	}
+}
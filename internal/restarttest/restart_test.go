@@ -0,0 +1,121 @@
+// Package restarttest 서버 재시작 테스트를 다른 테스트와 분리하여 실행합니다.
+// 재시작과 중지는 프로세스 전체의 서버에 영향을 주므로, 이 패키지는 자신의 TestMain에서 서버를 소유하고
+// 병렬 테스트나 데이터베이스 풀을 사용하지 않습니다.
+package restarttest_test
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"os"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/tidylogic/pgtestkit"
+)
+
+// srv 이 패키지의 테스트가 시작, 중지, 재시작하는 서버입니다.
+var srv *pgtestkit.Server
+
+// sqlConnector 연결 문자열을 database/sql로 여는 DBConnector입니다.
+type sqlConnector struct {
+	db *sql.DB
+}
+
+func (c *sqlConnector) Connect(connString string) (interface{}, error) {
+	db, err := sql.Open("pgx", connString)
+	if err != nil {
+		return nil, err
+	}
+	c.db = db
+	return db, nil
+}
+
+func (c *sqlConnector) Close() error {
+	if c.db == nil {
+		return nil
+	}
+	return c.db.Close()
+}
+
+func (c *sqlConnector) Reset() error {
+	return nil
+}
+
+func TestServerRestart(t *testing.T) {
+	dbClient, _ := pgtestkit.New(t, &sqlConnector{})
+	db := dbClient.Client.(*sql.DB)
+	if _, err := db.Exec(`CREATE TABLE survivors (id INT PRIMARY KEY); INSERT INTO survivors VALUES (1)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+
+	port := srv.Port()
+	if err := srv.Restart(); err != nil {
+		t.Fatalf("Failed to restart server: %v", err)
+	}
+	if srv.Port() != port {
+		t.Errorf("Expected port %d after restart, got %d", port, srv.Port())
+	}
+
+	// 재시작 전의 연결은 끊어지므로 재연결될 때까지 재시도
+	var count int
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = db.QueryRow(`SELECT count(*) FROM survivors`).Scan(&count)
+		if err == nil || attempt == 3 {
+			break
+		}
+		t.Logf("Query after restart failed (attempt %d): %v", attempt, err)
+	}
+	if err != nil {
+		t.Fatalf("Failed to query after restart: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("Expected the row to survive the restart, got %d rows", count)
+	}
+
+	// 재시작한 서버에서도 새 데이터베이스를 만들 수 있어야 함
+	pgtestkit.New(t, &sqlConnector{})
+}
+
+func TestServerStopStart(t *testing.T) {
+	if err := srv.Stop(); err != nil {
+		t.Fatalf("Failed to stop server: %v", err)
+	}
+	if srv.Running() {
+		t.Error("Expected the server to be stopped")
+	}
+	_, err := pgtestkit.CreateTestDB(&sqlConnector{})
+	if !errors.Is(err, pgtestkit.ErrServerNotRunning) || !errors.Is(err, pgtestkit.ErrServerStopped) {
+		t.Errorf("Expected ErrServerNotRunning and ErrServerStopped, got %v", err)
+	}
+
+	// 중지한 서버를 같은 프로세스에서 다시 시작 (TestMain이 마지막에 중지)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Failed to start server again: %v", err)
+	}
+	if !srv.Running() {
+		t.Error("Expected the server to be running")
+	}
+	pgtestkit.New(t, &sqlConnector{})
+}
+
+func TestMain(m *testing.M) {
+	var err error
+	srv, err = pgtestkit.NewServer()
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
+	if err := srv.Start(); err != nil {
+		log.Fatalf("Failed to start server: %v", err)
+	}
+
+	code := m.Run()
+	if err := srv.Stop(); err != nil {
+		log.Printf("Failed to stop server: %v", err)
+		if code == 0 {
+			code = 1
+		}
+	}
+	os.Exit(code)
+}
@@ -0,0 +1,110 @@
+package pgtestkit
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// KeepOnFailureEnv 실패한 테스트의 데이터베이스를 보존할지 지정하는 환경 변수입니다.
+	// strconv.ParseBool이 이해하는 값(1, true 등)을 사용합니다.
+	KeepOnFailureEnv = "PGTESTKIT_KEEP_ON_FAILURE"
+)
+
+var (
+	// 실패한 테스트의 데이터베이스가 보존되어 데이터 디렉토리를 지우면 안 되는지 여부 (serverMutex로 보호)
+	preserveDataDirectory bool
+
+	// 이 프로세스가 보존한 데이터베이스 이름 (serverMutex로 보호)
+	keptDatabases []string
+)
+
+// WithTB 테스트 데이터베이스를 소유한 테스트를 지정합니다.
+// WithKeepOnFailure와 함께 사용하면 Close 시점에 테스트의 실패 여부를 확인합니다.
+// New와 NewOf는 이 옵션을 자동으로 설정합니다.
+func WithTB(t testing.TB) Option {
+	return func(o *dbOptions) {
+		o.tb = t
+	}
+}
+
+// WithKeepOnFailure 소유한 테스트가 실패하면 Close에서 데이터베이스를 삭제하지 않고 보존합니다.
+// 보존된 경우 psql 연결 명령과 서버 재시작 명령이 테스트 로그에 출력되고, StopPostgres는 서버의 데이터 디렉토리를
+// 지우지 않습니다. 보존된 데이터베이스를 모두 직접 삭제했다면 데이터 디렉토리도 지웁니다.
+// 이 옵션을 지정하지 않으면 PGTESTKIT_KEEP_ON_FAILURE 환경 변수의 값을 따릅니다.
+func WithKeepOnFailure(keep bool) Option {
+	return func(o *dbOptions) {
+		o.keepOnFailure = &keep
+	}
+}
+
+// envBool strconv.ParseBool로 불리언 환경 변수를 읽습니다. 비어 있거나 잘못된 값이면 false입니다.
+func envBool(name string) bool {
+	value := os.Getenv(name)
+	if value == "" {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		getLogger().Warn("Ignoring invalid environment variable",
+			zap.String("name", name),
+			zap.String("value", value))
+		return false
+	}
+	return enabled
+}
+
+// shouldKeep 소유한 테스트가 실패하여 데이터베이스를 보존해야 하는지 확인합니다.
+func (c *DBClient) shouldKeep() bool {
+	return c.keepOnFailure && c.tb != nil && c.tb.Failed()
+}
+
+// reportKeptDatabase 보존된 데이터베이스에 접속하는 방법을 테스트 로그에 출력합니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func (c *DBClient) reportKeptDatabase() {
+	preserveDataDirectory = true
+	if !slices.Contains(keptDatabases, c.DBName) {
+		keptDatabases = append(keptDatabases, c.DBName)
+	}
+
+	c.tb.Logf("pgtestkit: test failed, keeping database %s for inspection:\n\tpsql %q",
+		c.DBName, c.ConnectionString)
+	if runtimeDirectory != "" {
+		c.tb.Logf("pgtestkit: the server data directory will be preserved; to restart the server later run:\n\t%s",
+			restartCommand())
+	}
+
+	getLogger().Info("Kept database of failed test",
+		zap.String("database", c.DBName),
+		zap.String("test", c.tb.Name()))
+}
+
+// keptDatabasesRemain 보존한 데이터베이스가 아직 남아 있는지 확인합니다. 확인할 수 없으면 남아 있다고 봅니다.
+// 모두 직접 삭제했다면 서버를 중지할 때 데이터 디렉토리를 보존하지 않아도 됩니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func keptDatabasesRemain(ctx context.Context) bool {
+	if len(keptDatabases) == 0 || baseDBClient == nil {
+		return true
+	}
+	var remaining int
+	err := baseDBClient.QueryRowContext(ctx,
+		"SELECT count(*) FROM pg_database WHERE datname = ANY($1)", keptDatabases).Scan(&remaining)
+	if err != nil {
+		getLogger().Debug("Failed to check preserved databases", zap.Error(err))
+		return true
+	}
+	return remaining > 0
+}
+
+// restartCommand 보존된 데이터 디렉토리로 서버를 다시 시작하는 명령을 생성합니다.
+func restartCommand() string {
+	return fmt.Sprintf("%s start -D %q -o \"-p %d\"",
+		filepath.Join(binariesDirectory, "bin", "pg_ctl"), dataDirectory, port)
+}
@@ -0,0 +1,57 @@
+package pgtestkit
+
+import "testing"
+
+// failedTB 실패한 테스트처럼 동작하는 testing.TB입니다.
+type failedTB struct {
+	testing.TB
+	failed bool
+}
+
+func (f *failedTB) Failed() bool { return f.failed }
+
+func TestEnvBool(t *testing.T) {
+	tests := map[string]bool{
+		"":      false,
+		"1":     true,
+		"true":  true,
+		"FALSE": false,
+		"yes":   false, // strconv.ParseBool이 이해하지 못하는 값은 무시
+	}
+	for value, want := range tests {
+		t.Setenv(KeepOnFailureEnv, value)
+		if got := envBool(KeepOnFailureEnv); got != want {
+			t.Errorf("envBool(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestShouldKeep(t *testing.T) {
+	tests := []struct {
+		name   string
+		keep   bool
+		tb     testing.TB
+		expect bool
+	}{
+		{name: "failed test", keep: true, tb: &failedTB{TB: t, failed: true}, expect: true},
+		{name: "passed test", keep: true, tb: &failedTB{TB: t}},
+		{name: "option disabled", tb: &failedTB{TB: t, failed: true}},
+		{name: "no test", keep: true},
+	}
+	for _, tt := range tests {
+		c := &DBClient{keepOnFailure: tt.keep, tb: tt.tb}
+		if got := c.shouldKeep(); got != tt.expect {
+			t.Errorf("%s: shouldKeep() = %v, want %v", tt.name, got, tt.expect)
+		}
+	}
+}
+
+func TestKeepOnFailureOption(t *testing.T) {
+	t.Setenv(KeepOnFailureEnv, "1")
+	if o := newDBOptions(nil); !*o.keepOnFailure {
+		t.Error("Expected the environment variable to enable keeping databases")
+	}
+	if o := newDBOptions([]Option{WithKeepOnFailure(false)}); *o.keepOnFailure {
+		t.Error("Expected WithKeepOnFailure to override the environment variable")
+	}
+}
@@ -1,35 +1,88 @@
 package pgtestkit
 
 import (
+	"context"
+	"log/slog"
 	"os"
+	"sort"
 	"sync"
+	"sync/atomic"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
 var (
-	// privateLogger is the singleton logger instance for the package
+	// privateLogger is the singleton logger instance for the package.
+	// Its core forwards to the current sink, so loggers derived from it follow SetLogger.
 	privateLogger *zap.Logger
 	onceLogger    sync.Once
 
+	// defaultCore is the zap production core used until SetLogger or SetZapLogger is called
+	defaultCore     zapcore.Core
+	onceDefaultCore sync.Once
+
+	// logSink is the core set by SetLogger or SetZapLogger (nil means defaultCore)
+	logSink atomic.Pointer[zapcore.Core]
+
 	// enableLogging controls whether logging is enabled
-	enableLogging = false
+	enableLogging atomic.Bool
 )
 
-// SetLogging enables or disables logging
+// SetLogging enables or disables logging.
+// Every log call of the package is gated by this switch, regardless of the logger in use.
 func SetLogging(enabled bool) {
-	enableLogging = enabled
+	enableLogging.Store(enabled)
 }
 
 // IsLoggingEnabled returns whether logging is currently enabled
 func IsLoggingEnabled() bool {
-	return enableLogging
+	return enableLogging.Load()
 }
 
-// getLogger returns a thread-safe singleton logger instance
-func getLogger() *zap.Logger {
-	onceLogger.Do(func() {
+// SetLogger sends the package's logs to logger and enables logging.
+// Passing nil restores the default stderr logger and disables logging.
+// Records carry "database", "schema" and "test" attributes where they apply.
+//
+// Example:
+//
+//	pgtestkit.SetLogger(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})))
+func SetLogger(logger *slog.Logger) {
+	if logger == nil {
+		setLogSink(nil)
+		return
+	}
+	setLogSink(&slogCore{handler: logger.Handler()})
+}
+
+// SetZapLogger sends the package's logs to logger's core and enables logging.
+// Passing nil restores the default stderr logger and disables logging.
+// The package never modifies zap's global loggers.
+func SetZapLogger(logger *zap.Logger) {
+	if logger == nil {
+		setLogSink(nil)
+		return
+	}
+	setLogSink(logger.Core())
+}
+
+// setLogSink replaces the sink and enables logging, or restores the default when core is nil
+func setLogSink(core zapcore.Core) {
+	if core == nil {
+		logSink.Store(nil)
+		enableLogging.Store(false)
+		return
+	}
+	logSink.Store(&core)
+	enableLogging.Store(true)
+}
+
+// currentSink returns the core that receives log entries
+func currentSink() zapcore.Core {
+	if core := logSink.Load(); core != nil {
+		return *core
+	}
+	onceDefaultCore.Do(func() {
 		// Configure the logger with production settings
 		config := zap.NewProductionConfig()
 
@@ -48,53 +101,151 @@ func getLogger() *zap.Logger {
 			config.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 		}
 
-		// Build the logger
-		var err error
-		privateLogger, err = config.Build()
+		logger, err := config.Build()
 		if err != nil {
 			// Fallback to a basic logger if there's an error
-			privateLogger = zap.NewExample()
+			logger = zap.NewExample()
 		}
+		defaultCore = logger.Core()
+	})
+	return defaultCore
+}
 
+// getLogger returns a thread-safe singleton logger instance.
+// Entries are dropped unless logging is enabled, and are written to the current sink.
+func getLogger() *zap.Logger {
+	onceLogger.Do(func() {
 		// Add caller information to log entries
-		privateLogger = privateLogger.WithOptions(zap.AddCaller())
-
-		// Set the global logger
-		zap.ReplaceGlobals(privateLogger)
+		privateLogger = zap.New(&gatedCore{}, zap.AddCaller())
 	})
-
 	return privateLogger
 }
 
+// logger returns the package logger carrying the client's database, schema and test name
+func (c *DBClient) logger() *zap.Logger {
+	logger := getLogger().With(zap.String("database", c.DBName))
+	if c.Schema != "" {
+		logger = logger.With(zap.String("schema", c.Schema))
+	}
+	if c.tb != nil {
+		logger = logger.With(zap.String("test", c.tb.Name()))
+	}
+	return logger
+}
+
+// gatedCore checks SetLogging on every entry and forwards enabled entries to the current sink
+type gatedCore struct {
+	fields []zapcore.Field
+}
+
+func (c *gatedCore) Enabled(level zapcore.Level) bool {
+	return enableLogging.Load() && currentSink().Enabled(level)
+}
+
+func (c *gatedCore) With(fields []zapcore.Field) zapcore.Core {
+	return &gatedCore{fields: append(c.fields[:len(c.fields):len(c.fields)], fields...)}
+}
+
+func (c *gatedCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *gatedCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	core := currentSink()
+	if len(c.fields) > 0 {
+		core = core.With(c.fields)
+	}
+	return core.Write(entry, fields)
+}
+
+func (c *gatedCore) Sync() error {
+	return currentSink().Sync()
+}
+
+// slogCore adapts a slog.Handler to zapcore.Core
+type slogCore struct {
+	handler slog.Handler
+}
+
+func (c *slogCore) Enabled(level zapcore.Level) bool {
+	return c.handler.Enabled(context.Background(), slogLevel(level))
+}
+
+func (c *slogCore) With(fields []zapcore.Field) zapcore.Core {
+	return &slogCore{handler: c.handler.WithAttrs(slogAttrs(fields))}
+}
+
+func (c *slogCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *slogCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	record := slog.NewRecord(entry.Time, slogLevel(entry.Level), entry.Message, entry.Caller.PC)
+	record.AddAttrs(slogAttrs(fields)...)
+	return c.handler.Handle(context.Background(), record)
+}
+
+func (c *slogCore) Sync() error {
+	return nil
+}
+
+// slogLevel maps a zap level to the closest slog level
+func slogLevel(level zapcore.Level) slog.Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return slog.LevelDebug
+	case level == zapcore.InfoLevel:
+		return slog.LevelInfo
+	case level == zapcore.WarnLevel:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
+
+// slogAttrs converts zap fields to slog attributes, sorted by key
+func slogAttrs(fields []zapcore.Field) []slog.Attr {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+
+	keys := make([]string, 0, len(enc.Fields))
+	for key := range enc.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, len(keys))
+	for i, key := range keys {
+		attrs[i] = slog.Any(key, enc.Fields[key])
+	}
+	return attrs
+}
+
 // logError logs an error message with additional context
 func logError(msg string, err error, fields ...zap.Field) {
-	if !enableLogging {
-		return
-	}
 	allFields := append([]zap.Field{zap.Error(err)}, fields...)
 	getLogger().Error(msg, allFields...)
 }
 
 // logInfo logs an info message with additional context
 func logInfo(msg string, fields ...zap.Field) {
-	if !enableLogging {
-		return
-	}
 	getLogger().Info(msg, fields...)
 }
 
 // logDebug logs a debug message with additional context
 func logDebug(msg string, fields ...zap.Field) {
-	if !enableLogging {
-		return
-	}
 	getLogger().Debug(msg, fields...)
 }
 
 // logWarn logs a warning message with additional context
 func logWarn(msg string, fields ...zap.Field) {
-	if !enableLogging {
-		return
-	}
 	getLogger().Warn(msg, fields...)
 }
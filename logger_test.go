@@ -0,0 +1,81 @@
+package pgtestkit
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestSetLogger(t *testing.T) {
+	enabled := IsLoggingEnabled()
+	defer func() {
+		SetLogger(nil)
+		SetLogging(enabled)
+	}()
+
+	var buf bytes.Buffer
+	SetLogger(slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+	if !IsLoggingEnabled() {
+		t.Fatal("Expected SetLogger to enable logging")
+	}
+
+	// 로거를 바꾸기 전에 만든 로거도 새 로거로 기록해야 함
+	c := &DBClient{DBName: "testdb_x", Schema: "test_s", tb: t}
+	c.logger().Debug("Resetting", zap.Int("tables", 3))
+	logError("Failed", errors.New("boom"))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 records, got %q", buf.String())
+	}
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record["msg"] != "Resetting" || record["level"] != "DEBUG" || record["database"] != "testdb_x" ||
+		record["schema"] != "test_s" || record["test"] != t.Name() || record["tables"] != float64(3) {
+		t.Errorf("Unexpected record: %v", record)
+	}
+	if !strings.Contains(lines[1], `"level":"ERROR"`) || !strings.Contains(lines[1], `"error":"boom"`) {
+		t.Errorf("Unexpected error record: %s", lines[1])
+	}
+
+	// SetLogging(false)는 모든 기록을 막아야 함
+	buf.Reset()
+	SetLogging(false)
+	getLogger().Info("Silenced")
+	c.logger().Error("Silenced")
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output with logging disabled, got %q", buf.String())
+	}
+
+	// 패키지는 zap의 전역 로거를 바꾸지 않아야 함
+	if zap.L().Core().Enabled(zapcore.ErrorLevel) {
+		t.Error("Expected zap global logger to be left untouched")
+	}
+}
+
+func TestSetZapLogger(t *testing.T) {
+	enabled := IsLoggingEnabled()
+	defer func() {
+		SetZapLogger(nil)
+		SetLogging(enabled)
+	}()
+
+	core, logs := observer.New(zapcore.InfoLevel)
+	SetZapLogger(zap.New(core))
+	getLogger().With(zap.String("database", "testdb_y")).Info("Created")
+	getLogger().Debug("Below level")
+
+	entries := logs.All()
+	if len(entries) != 1 || entries[0].Message != "Created" || entries[0].ContextMap()["database"] != "testdb_y" {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+}
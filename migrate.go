@@ -0,0 +1,562 @@
+package pgtestkit
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultMigrationsTable 적용된 마이그레이션을 기록하는 기본 테이블 이름입니다.
+	// 다른 마이그레이션 도구의 schema_migrations 테이블과 겹치지 않도록 별도의 이름을 사용합니다.
+	DefaultMigrationsTable = "pgtestkit_migrations"
+
+	// 파일 첫 줄에 이 주석이 있으면 트랜잭션 없이 실행 (CREATE INDEX CONCURRENTLY 등)
+	noTransactionDirective = "-- pgtestkit:no-transaction"
+)
+
+// 마이그레이션 파일 이름 형식: NNN_name.up.sql / NNN_name.down.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration fs.FS에서 읽은 하나의 마이그레이션입니다.
+type Migration struct {
+	Version  int64
+	Name     string
+	UpFile   string // up 파일 경로
+	DownFile string // down 파일 경로 (없으면 빈 문자열)
+	Up       string // up SQL
+	Down     string // down SQL
+}
+
+// MigrationError 마이그레이션 실행 중 실패한 파일과 문장, 위치를 담습니다.
+// Line과 Column은 파일 기준이며, PostgreSQL이 오류 위치를 알려주지 않으면 실패한 문장의 시작 위치입니다.
+type MigrationError struct {
+	File           string // 실패한 파일 경로
+	Version        int64  // 마이그레이션 버전
+	Statement      string // 실패한 SQL 문장
+	StatementIndex int    // 파일 안에서 문장의 순서 (1부터 시작)
+	Line           int    // 파일 안의 줄 번호 (1부터 시작)
+	Column         int    // 줄 안의 문자 위치 (1부터 시작)
+	Position       int    // 문장 안에서 PostgreSQL이 보고한 문자 위치 (없으면 0)
+	Err            error
+}
+
+// Error 파일, 위치, 문장 요약과 원인 오류를 포함한 메시지를 반환합니다.
+func (e *MigrationError) Error() string {
+	return fmt.Sprintf("migration %s failed at line %d, column %d (statement %d: %s): %v",
+		e.File, e.Line, e.Column, e.StatementIndex, summarizeStatement(e.Statement), e.Err)
+}
+
+// Unwrap 원인 오류를 반환합니다.
+func (e *MigrationError) Unwrap() error {
+	return e.Err
+}
+
+// MigrateOption 마이그레이션 실행 방법을 변경하는 옵션입니다.
+type MigrateOption func(*migrateConfig)
+
+// migrateConfig 마이그레이션 실행 설정입니다.
+type migrateConfig struct {
+	table string
+}
+
+// WithMigrationsTable 적용된 마이그레이션을 기록할 테이블 이름을 지정합니다. 기본값은 DefaultMigrationsTable입니다.
+func WithMigrationsTable(name string) MigrateOption {
+	return func(c *migrateConfig) {
+		c.table = name
+	}
+}
+
+// newMigrateConfig 기본 설정에 옵션을 적용하고 검증합니다.
+func newMigrateConfig(opts []MigrateOption) (migrateConfig, error) {
+	cfg := migrateConfig{table: DefaultMigrationsTable}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if cfg.table == "" {
+		return migrateConfig{}, fmt.Errorf("migrations table name cannot be empty")
+	}
+	return cfg, nil
+}
+
+// Migrator 연결 문자열이 가리키는 데이터베이스에 마이그레이션을 적용합니다.
+// SQLMigrator와 migrators 디렉토리의 어댑터(golang-migrate, goose, Atlas)가 이 인터페이스를 구현합니다.
+type Migrator interface {
+	Migrate(ctx context.Context, connString string) error
+}
+
+// MigratorFunc 함수를 Migrator로 사용할 수 있게 합니다.
+type MigratorFunc func(ctx context.Context, connString string) error
+
+// Migrate f를 호출합니다.
+func (f MigratorFunc) Migrate(ctx context.Context, connString string) error {
+	return f(ctx, connString)
+}
+
+// SQLMigrator fsys의 SQL 마이그레이션을 Migrate로 적용하는 Migrator를 반환합니다.
+func SQLMigrator(fsys fs.FS, opts ...MigrateOption) Migrator {
+	return MigratorFunc(func(ctx context.Context, connString string) error {
+		return migrateConnString(ctx, connString, fsys, opts)
+	})
+}
+
+// WithMigrations 테스트 데이터베이스(또는 스키마 격리 모드의 테스트 스키마)를 만든 뒤,
+// 커넥터가 연결하기 전에 fsys의 마이그레이션을 적용합니다.
+// 테스트마다 마이그레이션을 실행하므로, 마이그레이션이 많다면 MigrationSetup으로 템플릿을 한 번만 만드는 편이 빠릅니다.
+// 트랜잭션 격리 모드에서는 사용할 수 없습니다.
+func WithMigrations(fsys fs.FS, opts ...MigrateOption) Option {
+	return WithMigrator(SQLMigrator(fsys, opts...))
+}
+
+// WithMigrator WithMigrations와 같지만 임의의 Migrator를 사용합니다.
+func WithMigrator(m Migrator) Option {
+	return func(o *dbOptions) {
+		o.migrator = m
+	}
+}
+
+// MigrationSetup fsys의 마이그레이션을 적용하는 setup 함수를 반환합니다.
+// CreateTemplateDB나 CreateTemplateSchema에 전달하면 마이그레이션을 템플릿에 한 번만 적용할 수 있습니다.
+//
+// 사용 예시:
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	sub, _ := fs.Sub(migrations, "migrations")
+//	err := pgtestkit.CreateTemplateDB("golden", pgtestkit.MigrationSetup(sub))
+func MigrationSetup(fsys fs.FS, opts ...MigrateOption) func(connString string) error {
+	return MigratorSetup(SQLMigrator(fsys, opts...))
+}
+
+// MigratorSetup MigrationSetup과 같지만 임의의 Migrator를 사용합니다.
+func MigratorSetup(m Migrator) func(connString string) error {
+	return func(connString string) error {
+		return m.Migrate(context.Background(), connString)
+	}
+}
+
+// WithMigratedTemplate 서버가 시작되면 name 템플릿 데이터베이스를 만들고 m으로 마이그레이션한 뒤 기본 템플릿으로 등록합니다.
+// RunTests에서 사용하면 TestMain에 마이그레이션 코드를 두지 않아도 됩니다.
+// 템플릿은 CreateTemplateDB로 만들므로, 공유 서버나 외부 서버에서는 패키지마다 다른 이름을 사용하세요.
+// 다른 프로세스의 템플릿과 이름이 겹치면 서버 시작이 ErrTemplateExists로 실패합니다.
+//
+// 사용 예시:
+//
+//	os.Exit(pgtestkit.RunTests(m, pgtestkit.WithMigratedTemplate("golden", pgtestkit.SQLMigrator(migrations))))
+func WithMigratedTemplate(name string, m Migrator) ServerOption {
+	return func(c *serverConfig) {
+		c.templateName = name
+		c.templateMigrator = m
+	}
+}
+
+// LoadMigrations fsys의 최상위 디렉토리에서 NNN_name.up.sql / NNN_name.down.sql 파일을 읽어 버전 순으로 반환합니다.
+// 하위 디렉토리의 파일을 사용하려면 fs.Sub를 사용하세요. .sql이 아닌 파일은 무시하며,
+// 형식이 맞지 않는 .sql 파일, 중복된 버전, up 파일이 없는 down 파일은 오류입니다.
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	if fsys == nil {
+		return nil, fmt.Errorf("migrations file system must not be nil")
+	}
+
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration file %s does not match NNN_name.up.sql or NNN_name.down.sql", entry.Name())
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration file %s: %w", entry.Name(), err)
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, m.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			if m.UpFile != "" {
+				return nil, fmt.Errorf("duplicate up migration for version %d: %s", version, entry.Name())
+			}
+			m.UpFile, m.Up = entry.Name(), string(content)
+		} else {
+			if m.DownFile != "" {
+				return nil, fmt.Errorf("duplicate down migration for version %d: %s", version, entry.Name())
+			}
+			m.DownFile, m.Down = entry.Name(), string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpFile == "" {
+			return nil, fmt.Errorf("migration %s has no up file", m.DownFile)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return migrations, nil
+}
+
+// Migrate 아직 적용되지 않은 마이그레이션을 버전 순으로 적용합니다.
+// 각 파일은 하나의 트랜잭션에서 실행되며, 적용된 버전은 마이그레이션 테이블에 기록됩니다.
+// 파일 첫 줄이 "-- pgtestkit:no-transaction"이면 트랜잭션 없이 실행합니다.
+// SQL 문장이 실패하면 *MigrationError를 반환합니다.
+func Migrate(ctx context.Context, db *sql.DB, fsys fs.FS, opts ...MigrateOption) error {
+	cfg, err := newMigrateConfig(opts)
+	if err != nil {
+		return err
+	}
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return err
+	}
+	applied, err := appliedMigrations(ctx, db, cfg.table)
+	if err != nil {
+		return err
+	}
+
+	logger := getLogger()
+	for _, m := range migrations {
+		if applied[m.Version] {
+			continue
+		}
+		logger.Debug("Applying migration", zap.String("file", m.UpFile))
+		if err := runMigration(ctx, db, cfg.table, m, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MigrateDown 적용된 마이그레이션을 최신 버전부터 steps개 되돌립니다. steps가 0 이하이면 모두 되돌립니다.
+// 되돌릴 마이그레이션에 down 파일이 없으면 오류를 반환합니다.
+func MigrateDown(ctx context.Context, db *sql.DB, fsys fs.FS, steps int, opts ...MigrateOption) error {
+	cfg, err := newMigrateConfig(opts)
+	if err != nil {
+		return err
+	}
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return err
+	}
+	applied, err := appliedMigrations(ctx, db, cfg.table)
+	if err != nil {
+		return err
+	}
+
+	logger := getLogger()
+	reverted := 0
+	for _, m := range slices.Backward(migrations) {
+		if steps > 0 && reverted == steps {
+			break
+		}
+		if !applied[m.Version] {
+			continue
+		}
+		if m.DownFile == "" {
+			return fmt.Errorf("migration %s has no down file", m.UpFile)
+		}
+		logger.Debug("Reverting migration", zap.String("file", m.DownFile))
+		if err := runMigration(ctx, db, cfg.table, m, false); err != nil {
+			return err
+		}
+		reverted++
+	}
+	return nil
+}
+
+// migrateConnString 연결 문자열로 연결하여 마이그레이션을 적용합니다.
+func migrateConnString(ctx context.Context, connString string, fsys fs.FS, opts []MigrateOption) error {
+	db, err := sql.Open("pgx", connString)
+	if err != nil {
+		return fmt.Errorf("failed to open database for migrations: %w", err)
+	}
+	defer db.Close()
+
+	return Migrate(ctx, db, fsys, opts...)
+}
+
+// appliedMigrations 마이그레이션 테이블을 만들고 적용된 버전을 반환합니다.
+func appliedMigrations(ctx context.Context, db *sql.DB, table string) (map[int64]bool, error) {
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version BIGINT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now())`, quoteIdentifier(table)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrations table %s: %w", table, err)
+	}
+
+	rows, err := db.QueryContext(ctx, "SELECT version FROM "+quoteIdentifier(table))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations table %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]bool)
+	for rows.Next() {
+		var version int64
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("failed to scan migration version: %w", err)
+		}
+		applied[version] = true
+	}
+	return applied, rows.Err()
+}
+
+// sqlExecer 트랜잭션과 연결 모두에서 문장을 실행하기 위한 인터페이스입니다.
+type sqlExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// runMigration 마이그레이션 하나를 적용하거나 되돌리고 마이그레이션 테이블을 갱신합니다.
+func runMigration(ctx context.Context, db *sql.DB, table string, m Migration, up bool) error {
+	file, src := m.UpFile, m.Up
+	record := fmt.Sprintf("INSERT INTO %s (version, name) VALUES ($1, $2)", quoteIdentifier(table))
+	args := []any{m.Version, m.Name}
+	if !up {
+		file, src = m.DownFile, m.Down
+		record = fmt.Sprintf("DELETE FROM %s WHERE version = $1", quoteIdentifier(table))
+		args = args[:1]
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(src), noTransactionDirective) {
+		if err := execStatements(ctx, db, file, m.Version, src); err != nil {
+			return err
+		}
+		if _, err := db.ExecContext(ctx, record, args...); err != nil {
+			return fmt.Errorf("failed to record migration %s: %w", file, err)
+		}
+		return nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %s: %w", file, err)
+	}
+	defer tx.Rollback()
+
+	if err := execStatements(ctx, tx, file, m.Version, src); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", file, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit migration %s: %w", file, err)
+	}
+	return nil
+}
+
+// execStatements 파일의 문장을 하나씩 실행하고, 실패하면 파일 기준 위치를 담은 MigrationError를 반환합니다.
+func execStatements(ctx context.Context, db sqlExecer, file string, version int64, src string) error {
+	for i, stmt := range splitStatements(src) {
+		if _, err := db.ExecContext(ctx, stmt.text); err != nil {
+			migErr := &MigrationError{
+				File:           file,
+				Version:        version,
+				Statement:      stmt.text,
+				StatementIndex: i + 1,
+				Err:            err,
+			}
+			offset := stmt.offset
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Position > 0 {
+				migErr.Position = int(pgErr.Position)
+				offset += runeOffset(stmt.text, migErr.Position-1)
+			}
+			migErr.Line, migErr.Column = lineColumn(src, offset)
+			return migErr
+		}
+	}
+	return nil
+}
+
+// sqlStatement 파일에서 분리한 SQL 문장과 파일 안의 시작 위치(바이트)입니다.
+type sqlStatement struct {
+	text   string
+	offset int
+}
+
+// splitStatements SQL을 세미콜론 기준으로 문장 단위로 나눕니다.
+// 문자열, 따옴표 식별자, 달러 인용, 주석 안의 세미콜론은 무시하며, 문장 앞의 주석과 주석만 있는 문장은 건너뜁니다.
+func splitStatements(src string) []sqlStatement {
+	var stmts []sqlStatement
+	start, hasCode := 0, false
+	flush := func(end int) {
+		if hasCode {
+			stmts = append(stmts, sqlStatement{
+				text:   strings.TrimRight(src[start:end], " \t\r\n"),
+				offset: start,
+			})
+		}
+		hasCode = false
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '-' && strings.HasPrefix(src[i:], "--"):
+			if j := strings.IndexByte(src[i:], '\n'); j >= 0 {
+				i += j + 1
+			} else {
+				i = len(src)
+			}
+		case c == '/' && strings.HasPrefix(src[i:], "/*"):
+			i = skipBlockComment(src, i)
+		case c == ';':
+			flush(i)
+			i++
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		default:
+			// 문장은 앞의 공백과 주석을 제외한 첫 코드부터 시작
+			if !hasCode {
+				start, hasCode = i, true
+			}
+			switch {
+			case c == '\'':
+				escapes := i > 0 && (src[i-1] == 'E' || src[i-1] == 'e') && (i < 2 || !isIdentByte(src[i-2]))
+				i = skipQuoted(src, i, '\'', escapes)
+			case c == '"':
+				i = skipQuoted(src, i, '"', false)
+			case c == '$':
+				if tag := dollarTag(src, i); tag != "" {
+					if j := strings.Index(src[i+len(tag):], tag); j >= 0 {
+						i += len(tag) + j + len(tag)
+					} else {
+						i = len(src)
+					}
+				} else {
+					i++
+				}
+			default:
+				i++
+			}
+		}
+	}
+	flush(len(src))
+	return stmts
+}
+
+// skipBlockComment 중첩을 고려하여 /* */ 주석의 끝 다음 위치를 반환합니다.
+func skipBlockComment(src string, i int) int {
+	depth := 0
+	for i < len(src) {
+		switch {
+		case strings.HasPrefix(src[i:], "/*"):
+			depth++
+			i += 2
+		case strings.HasPrefix(src[i:], "*/"):
+			depth--
+			i += 2
+			if depth == 0 {
+				return i
+			}
+		default:
+			i++
+		}
+	}
+	return i
+}
+
+// skipQuoted 따옴표로 감싼 문자열이나 식별자의 끝 다음 위치를 반환합니다.
+// 따옴표 두 개는 이스케이프로 처리하며, backslash가 true이면 E'...' 문자열의 백슬래시 이스케이프도 처리합니다.
+func skipQuoted(src string, i int, quote byte, backslash bool) int {
+	for j := i + 1; j < len(src); j++ {
+		switch {
+		case backslash && src[j] == '\\':
+			j++
+		case src[j] == quote:
+			if j+1 < len(src) && src[j+1] == quote {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(src)
+}
+
+// dollarTag i에서 시작하는 달러 인용 태그($$ 또는 $tag$)를 반환합니다. 태그가 아니면 빈 문자열을 반환합니다.
+func dollarTag(src string, i int) string {
+	if i > 0 && isIdentByte(src[i-1]) {
+		return ""
+	}
+	for j := i + 1; j < len(src); j++ {
+		c := src[j]
+		if c == '$' {
+			return src[i : j+1]
+		}
+		if !isIdentByte(c) || (j == i+1 && c >= '0' && c <= '9') {
+			return ""
+		}
+	}
+	return ""
+}
+
+// isIdentByte 식별자에 사용할 수 있는 문자인지 확인합니다.
+func isIdentByte(c byte) bool {
+	return c == '_' || c >= 0x80 || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// runeOffset n번째 문자(0부터 시작)의 바이트 위치를 반환합니다.
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
+
+// lineColumn 바이트 위치를 1부터 시작하는 줄 번호와 문자 위치로 변환합니다.
+func lineColumn(src string, offset int) (int, int) {
+	offset = min(offset, len(src))
+	lineStart := strings.LastIndexByte(src[:offset], '\n') + 1
+	return strings.Count(src[:offset], "\n") + 1, utf8.RuneCountInString(src[lineStart:offset]) + 1
+}
+
+// summarizeStatement 오류 메시지에 넣을 수 있도록 문장을 한 줄로 줄입니다.
+func summarizeStatement(stmt string) string {
+	const maxLen = 60
+	s := strings.Join(strings.Fields(stmt), " ")
+	if utf8.RuneCountInString(s) > maxLen {
+		s = string([]rune(s)[:maxLen]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,83 @@
+package pgtestkit
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"002_add_email.up.sql":    {Data: []byte("ALTER TABLE users ADD email TEXT;")},
+		"001_init.up.sql":         {Data: []byte("CREATE TABLE users (id INT);")},
+		"001_init.down.sql":       {Data: []byte("DROP TABLE users;")},
+		"README.md":               {Data: []byte("ignored")},
+		"nested/003_other.up.sql": {Data: []byte("ignored")},
+		"010_indexes.up.sql":      {Data: []byte("CREATE INDEX ON users (id);")},
+	}
+
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("LoadMigrations() error = %v", err)
+	}
+	if len(migrations) != 3 {
+		t.Fatalf("LoadMigrations() returned %d migrations, want 3", len(migrations))
+	}
+	if m := migrations[0]; m.Version != 1 || m.Name != "init" || m.DownFile != "001_init.down.sql" {
+		t.Errorf("migrations[0] = %+v", m)
+	}
+	if migrations[1].Version != 2 || migrations[2].Version != 10 {
+		t.Errorf("Expected migrations ordered by version, got %d, %d", migrations[1].Version, migrations[2].Version)
+	}
+
+	invalid := map[string]fstest.MapFS{
+		"bad name":          {"init.up.sql": {}},
+		"duplicate version": {"001_a.up.sql": {}, "001_b.up.sql": {}},
+		"down without up":   {"001_a.down.sql": {}},
+	}
+	for name, fsys := range invalid {
+		if _, err := LoadMigrations(fsys); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	src := `-- leading comment; ignored
+CREATE TABLE a (s TEXT DEFAULT 'x;y', "we;ird" INT);
+/* block /* nested; */ comment */
+CREATE FUNCTION f() RETURNS INT AS $body$ SELECT 1; $body$ LANGUAGE sql;
+INSERT INTO a (s) VALUES (E'it\'s;');
+SELECT $1;
+-- trailing comment`
+
+	want := []string{
+		`CREATE TABLE a (s TEXT DEFAULT 'x;y', "we;ird" INT)`,
+		`CREATE FUNCTION f() RETURNS INT AS $body$ SELECT 1; $body$ LANGUAGE sql`,
+		`INSERT INTO a (s) VALUES (E'it\'s;')`,
+		`SELECT $1`,
+	}
+
+	stmts := splitStatements(src)
+	if len(stmts) != len(want) {
+		t.Fatalf("splitStatements() returned %d statements, want %d: %q", len(stmts), len(want), stmts)
+	}
+	for i, stmt := range stmts {
+		if stmt.text != want[i] {
+			t.Errorf("statement %d = %q, want %q", i, stmt.text, want[i])
+		}
+		if src[stmt.offset:stmt.offset+len(stmt.text)] != stmt.text {
+			t.Errorf("statement %d offset %d does not point at its text", i, stmt.offset)
+		}
+	}
+}
+
+func TestLineColumn(t *testing.T) {
+	src := "SELECT 1;\n\nSELECT 가나 FROM x;"
+	stmts := splitStatements(src)
+
+	// PostgreSQL은 문장 안의 문자 위치를 1부터 보고함
+	offset := stmts[1].offset + runeOffset(stmts[1].text, 13-1)
+	if line, col := lineColumn(src, offset); line != 3 || col != 13 {
+		t.Errorf("lineColumn() = %d:%d, want 3:13", line, col)
+	}
+}
@@ -0,0 +1,81 @@
+// Package atlasmigrate Atlas 마이그레이션 디렉토리를 pgtestkit.Migrator로 사용할 수 있게 합니다.
+//
+// 코어 모듈의 의존성을 늘리지 않도록 별도의 Go 모듈로 제공됩니다.
+// Atlas Go SDK(atlasexec)는 atlas CLI를 실행하므로 PATH에 atlas가 있거나 WithBinary로 경로를 지정해야 합니다.
+// 디렉토리의 atlas.sum 무결성 검사도 atlas migrate apply와 똑같이 수행됩니다.
+//
+// 사용 예시:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(pgtestkit.RunTests(m,
+//			pgtestkit.WithMigratedTemplate("golden", atlasmigrate.New(os.DirFS("migrations"))),
+//		))
+//	}
+package atlasmigrate
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+
+	"ariga.io/atlas-go-sdk/atlasexec"
+	"github.com/tidylogic/pgtestkit"
+)
+
+// DefaultBinary 기본으로 실행할 atlas CLI 이름입니다.
+const DefaultBinary = "atlas"
+
+// Option Atlas 실행 방법을 변경하는 옵션입니다.
+type Option func(*config)
+
+// config Atlas 실행 설정입니다.
+type config struct {
+	binary          string
+	revisionsSchema string
+}
+
+// WithBinary 실행할 atlas CLI의 경로를 지정합니다. 기본값은 PATH의 DefaultBinary입니다.
+func WithBinary(path string) Option {
+	return func(c *config) {
+		c.binary = path
+	}
+}
+
+// WithRevisionsSchema 적용된 버전을 기록할 스키마를 지정합니다. 기본값은 Atlas의 atlas_schema_revisions입니다.
+func WithRevisionsSchema(name string) Option {
+	return func(c *config) {
+		c.revisionsSchema = name
+	}
+}
+
+// New dir의 최상위 디렉토리에 있는 Atlas 마이그레이션(atlas.sum 포함)을 적용하는 Migrator를 반환합니다.
+func New(dir fs.FS, opts ...Option) pgtestkit.Migrator {
+	cfg := config{binary: DefaultBinary}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	return pgtestkit.MigratorFunc(func(ctx context.Context, connString string) error {
+		// atlas CLI는 파일 시스템의 디렉토리를 읽으므로 임시 작업 디렉토리에 마이그레이션을 복사
+		wd, err := atlasexec.NewWorkingDir(atlasexec.WithMigrations(dir))
+		if err != nil {
+			return fmt.Errorf("failed to prepare atlas working directory: %w", err)
+		}
+		defer wd.Close()
+
+		client, err := atlasexec.NewClient(wd.Path(), cfg.binary)
+		if err != nil {
+			return fmt.Errorf("failed to create atlas client: %w", err)
+		}
+
+		if _, err := client.MigrateApply(ctx, &atlasexec.MigrateApplyParams{
+			URL:             connString,
+			RevisionsSchema: cfg.revisionsSchema,
+		}); err != nil {
+			return fmt.Errorf("failed to apply atlas migrations: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,36 @@
+package atlasmigrate_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"os/exec"
+	"testing"
+
+	"github.com/tidylogic/pgtestkit"
+	"github.com/tidylogic/pgtestkit/migrators/atlasmigrate"
+)
+
+func TestMain(m *testing.M) {
+	// atlas CLI가 없는 환경에서는 건너뜀
+	if _, err := exec.LookPath(atlasmigrate.DefaultBinary); err != nil {
+		fmt.Println("atlas CLI not found, skipping atlasmigrate tests")
+		os.Exit(0)
+	}
+
+	migrations := atlasmigrate.New(os.DirFS("testdata/migrations"))
+	os.Exit(pgtestkit.RunTests(m, pgtestkit.WithMigratedTemplate("atlas_golden", migrations)))
+}
+
+func TestMigratedTemplate(t *testing.T) {
+	dbClient, _ := pgtestkit.New(t, &pgtestkit.TxSQLConnector{}, pgtestkit.WithTxIsolation())
+	db := dbClient.Client.(*sql.DB)
+
+	var name string
+	if err := db.QueryRow(`SELECT name FROM users`).Scan(&name); err != nil {
+		t.Fatalf("Failed to query migrated table: %v", err)
+	}
+	if name != "admin" {
+		t.Errorf("Expected seeded user 'admin', got %q", name)
+	}
+}
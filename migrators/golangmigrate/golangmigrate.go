@@ -0,0 +1,118 @@
+// Package golangmigrate golang-migrate의 마이그레이션 소스를 pgtestkit.Migrator로 사용할 수 있게 합니다.
+//
+// 코어 모듈의 의존성을 늘리지 않도록 별도의 Go 모듈로 제공됩니다.
+//
+// 사용 예시:
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(pgtestkit.RunTests(m,
+//			pgtestkit.WithMigratedTemplate("golden", golangmigrate.FromFS(migrations, "migrations")),
+//		))
+//	}
+package golangmigrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/golang-migrate/migrate/v4"
+	migratepgx "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/tidylogic/pgtestkit"
+)
+
+// Option golang-migrate 실행 방법을 변경하는 옵션입니다.
+type Option func(*config)
+
+// config golang-migrate 실행 설정입니다.
+type config struct {
+	table string
+}
+
+// WithMigrationsTable 적용된 버전을 기록할 테이블 이름을 지정합니다. 기본값은 golang-migrate의 schema_migrations입니다.
+func WithMigrationsTable(name string) Option {
+	return func(c *config) {
+		c.table = name
+	}
+}
+
+// FromFS fsys의 path 디렉토리에 있는 golang-migrate 형식(NNN_name.up.sql / .down.sql)의 마이그레이션을 적용하는
+// Migrator를 반환합니다.
+func FromFS(fsys fs.FS, path string, opts ...Option) pgtestkit.Migrator {
+	return newMigrator(func() (source.Driver, error) {
+		return iofs.New(fsys, path)
+	}, opts)
+}
+
+// FromSourceURL golang-migrate 소스 URL(예: "file://migrations")의 마이그레이션을 적용하는 Migrator를 반환합니다.
+// file 이외의 소스를 사용하려면 해당 소스 드라이버를 빈 import로 등록해야 합니다.
+func FromSourceURL(sourceURL string, opts ...Option) pgtestkit.Migrator {
+	return newMigrator(func() (source.Driver, error) {
+		return source.Open(sourceURL)
+	}, opts)
+}
+
+// newMigrator 실행할 때마다 소스를 새로 열어 마이그레이션을 적용하는 Migrator를 만듭니다.
+// golang-migrate의 소스는 Close 후 재사용할 수 없으므로 테스트 데이터베이스마다 다시 엽니다.
+func newMigrator(open func() (source.Driver, error), opts []Option) pgtestkit.Migrator {
+	var cfg config
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	return pgtestkit.MigratorFunc(func(ctx context.Context, connString string) error {
+		src, err := open()
+		if err != nil {
+			return fmt.Errorf("failed to open golang-migrate source: %w", err)
+		}
+
+		db, err := sql.Open("pgx", connString)
+		if err != nil {
+			_ = src.Close()
+			return fmt.Errorf("failed to open database for migrations: %w", err)
+		}
+
+		driver, err := migratepgx.WithInstance(db, &migratepgx.Config{MigrationsTable: cfg.table})
+		if err != nil {
+			_ = src.Close()
+			_ = db.Close()
+			return fmt.Errorf("failed to create golang-migrate database driver: %w", err)
+		}
+
+		m, err := migrate.NewWithInstance("pgtestkit", src, "pgx5", driver)
+		if err != nil {
+			_ = src.Close()
+			_ = driver.Close()
+			return fmt.Errorf("failed to create golang-migrate instance: %w", err)
+		}
+
+		// golang-migrate는 컨텍스트를 받지 않으므로 취소되면 GracefulStop으로 다음 마이그레이션 전에 멈춤
+		stop := context.AfterFunc(ctx, func() {
+			m.GracefulStop <- true
+		})
+		upErr := m.Up()
+		stop()
+
+		srcErr, dbErr := m.Close()
+		if upErr != nil && !errors.Is(upErr, migrate.ErrNoChange) {
+			return fmt.Errorf("failed to apply golang-migrate migrations: %w", upErr)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := errors.Join(srcErr, dbErr); err != nil {
+			return fmt.Errorf("failed to close golang-migrate instance: %w", err)
+		}
+		return nil
+	})
+}
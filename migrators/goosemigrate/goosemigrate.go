@@ -0,0 +1,100 @@
+// Package goosemigrate goose의 마이그레이션을 pgtestkit.Migrator로 사용할 수 있게 합니다.
+//
+// 코어 모듈의 의존성을 늘리지 않도록 별도의 Go 모듈로 제공됩니다.
+// SQL 마이그레이션과 goose.AddMigrationContext 등으로 등록한 Go 마이그레이션을 모두 적용합니다.
+//
+// 사용 예시:
+//
+//	//go:embed migrations/*.sql
+//	var migrations embed.FS
+//
+//	func TestMain(m *testing.M) {
+//		dir, _ := fs.Sub(migrations, "migrations")
+//		os.Exit(pgtestkit.RunTests(m,
+//			pgtestkit.WithMigratedTemplate("golden", goosemigrate.New(dir)),
+//		))
+//	}
+package goosemigrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/database"
+	"github.com/tidylogic/pgtestkit"
+)
+
+// Option goose 실행 방법을 변경하는 옵션입니다.
+type Option func(*config)
+
+// config goose 실행 설정입니다.
+type config struct {
+	table        string
+	providerOpts []goose.ProviderOption
+}
+
+// WithTableName 적용된 버전을 기록할 테이블 이름을 지정합니다. 기본값은 goose의 goose_db_version입니다.
+func WithTableName(name string) Option {
+	return func(c *config) {
+		c.table = name
+	}
+}
+
+// WithProviderOptions goose.NewProvider에 전달할 옵션을 추가합니다.
+func WithProviderOptions(opts ...goose.ProviderOption) Option {
+	return func(c *config) {
+		c.providerOpts = append(c.providerOpts, opts...)
+	}
+}
+
+// New fsys의 최상위 디렉토리에 있는 goose 마이그레이션을 적용하는 Migrator를 반환합니다.
+// 하위 디렉토리의 파일을 사용하려면 fs.Sub를 사용하세요.
+func New(fsys fs.FS, opts ...Option) pgtestkit.Migrator {
+	var cfg config
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	return pgtestkit.MigratorFunc(func(ctx context.Context, connString string) error {
+		db, err := sql.Open("pgx", connString)
+		if err != nil {
+			return fmt.Errorf("failed to open database for migrations: %w", err)
+		}
+
+		// 사용자 지정 저장소를 사용할 때는 dialect를 비워야 함
+		dialect := goose.DialectPostgres
+		providerOpts := cfg.providerOpts
+		if cfg.table != "" {
+			store, err := database.NewStore(database.DialectPostgres, cfg.table)
+			if err != nil {
+				_ = db.Close()
+				return fmt.Errorf("failed to create goose store: %w", err)
+			}
+			dialect = ""
+			providerOpts = append(providerOpts[:len(providerOpts):len(providerOpts)], goose.WithStore(store))
+		}
+
+		provider, err := goose.NewProvider(dialect, db, fsys, providerOpts...)
+		if err != nil {
+			_ = db.Close()
+			return fmt.Errorf("failed to create goose provider: %w", err)
+		}
+
+		// Close는 db도 함께 닫음
+		_, upErr := provider.Up(ctx)
+		closeErr := provider.Close()
+		if upErr != nil {
+			return fmt.Errorf("failed to apply goose migrations: %w", upErr)
+		}
+		if closeErr != nil {
+			return fmt.Errorf("failed to close goose provider: %w", closeErr)
+		}
+		return nil
+	})
+}
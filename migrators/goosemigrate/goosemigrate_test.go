@@ -0,0 +1,28 @@
+package goosemigrate_test
+
+import (
+	"database/sql"
+	"os"
+	"testing"
+
+	"github.com/tidylogic/pgtestkit"
+	"github.com/tidylogic/pgtestkit/migrators/goosemigrate"
+)
+
+func TestMain(m *testing.M) {
+	migrations := goosemigrate.New(os.DirFS("testdata/migrations"))
+	os.Exit(pgtestkit.RunTests(m, pgtestkit.WithMigratedTemplate("goose_golden", migrations)))
+}
+
+func TestMigratedTemplate(t *testing.T) {
+	dbClient, _ := pgtestkit.New(t, &pgtestkit.TxSQLConnector{}, pgtestkit.WithTxIsolation())
+	db := dbClient.Client.(*sql.DB)
+
+	var name string
+	if err := db.QueryRow(`SELECT name FROM users`).Scan(&name); err != nil {
+		t.Fatalf("Failed to query migrated table: %v", err)
+	}
+	if name != "admin" {
+		t.Errorf("Expected seeded user 'admin', got %q", name)
+	}
+}
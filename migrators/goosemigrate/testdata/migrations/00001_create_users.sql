@@ -0,0 +1,5 @@
+-- +goose Up
+CREATE TABLE users (id SERIAL PRIMARY KEY, name TEXT NOT NULL);
+
+-- +goose Down
+DROP TABLE users;
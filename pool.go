@@ -0,0 +1,482 @@
+package pgtestkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultPoolSize 미리 만들어 둘 테스트 데이터베이스의 기본 개수입니다.
+	DefaultPoolSize = 4
+
+	// 유휴 데이터베이스를 확인하는 최소 주기 (WithPoolMaxIdle이 아주 짧아도 타이머 주기가 0이 되지 않도록 함)
+	minPoolExpireInterval = time.Millisecond
+)
+
+var (
+	// 미리 만들어 둔 테스트 데이터베이스 풀 (serverMutex로 보호, EnableDBPool 전에는 nil)
+	dbPool *databasePool
+)
+
+// PoolOption 테스트 데이터베이스 풀의 설정을 변경하는 옵션입니다.
+type PoolOption func(*poolConfig)
+
+// poolConfig 테스트 데이터베이스 풀 설정입니다.
+type poolConfig struct {
+	size        int
+	lowWater    int // 준비된 데이터베이스가 이 수보다 적어지면 size까지 다시 채움 (-1이면 size/2)
+	maxIdle     time.Duration
+	template    string
+	useTemplate bool
+}
+
+// WithPoolSize 미리 만들어 둘 데이터베이스 수를 지정합니다. 기본값은 DefaultPoolSize입니다.
+func WithPoolSize(size int) PoolOption {
+	return func(c *poolConfig) {
+		c.size = size
+	}
+}
+
+// WithPoolLowWater 준비된 데이터베이스가 이 수보다 적어지면 풀을 다시 채웁니다. 기본값은 풀 크기의 절반입니다.
+func WithPoolLowWater(n int) PoolOption {
+	return func(c *poolConfig) {
+		c.lowWater = n
+	}
+}
+
+// WithPoolMaxIdle 사용되지 않은 채 지정한 시간이 지난 데이터베이스를 삭제합니다.
+// 삭제된 자리는 다음 CreateTestDB 호출 때 다시 채워지므로, 테스트가 없는 동안 풀이 줄어듭니다.
+// 유휴 데이터베이스는 d의 절반마다(최소 1ms) 확인합니다. 기본값(0)은 유휴 데이터베이스를 삭제하지 않습니다.
+func WithPoolMaxIdle(d time.Duration) PoolOption {
+	return func(c *poolConfig) {
+		c.maxIdle = d
+	}
+}
+
+// WithPoolTemplate 풀의 데이터베이스를 지정한 템플릿으로 만듭니다.
+// 지정하지 않으면 기본 템플릿(CreateTemplateDB, SetDefaultTemplate)을 따르며, 기본 템플릿이 바뀌면 풀을 다시 채웁니다.
+func WithPoolTemplate(name string) PoolOption {
+	return func(c *poolConfig) {
+		c.template = name
+		c.useTemplate = true
+	}
+}
+
+// newPoolConfig 기본 설정에 옵션을 적용하고 검증합니다.
+func newPoolConfig(opts []PoolOption) (poolConfig, error) {
+	cfg := poolConfig{size: DefaultPoolSize, lowWater: -1}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	if cfg.size <= 0 {
+		return poolConfig{}, fmt.Errorf("invalid pool configuration: size must be positive, got %d", cfg.size)
+	}
+	if cfg.lowWater == -1 {
+		cfg.lowWater = (cfg.size + 1) / 2
+	}
+	if cfg.lowWater < 0 || cfg.lowWater > cfg.size {
+		return poolConfig{}, fmt.Errorf("invalid pool configuration: low-water mark must be between 0 and %d, got %d",
+			cfg.size, cfg.lowWater)
+	}
+	if cfg.maxIdle < 0 {
+		return poolConfig{}, fmt.Errorf("invalid pool configuration: max idle must not be negative, got %s", cfg.maxIdle)
+	}
+	return cfg, nil
+}
+
+// PoolStats 테스트 데이터베이스 풀의 통계입니다. 풀 크기와 low-water 값을 조정할 때 참고합니다.
+type PoolStats struct {
+	Ready    int    // 지금 바로 사용할 수 있는 데이터베이스 수
+	Size     int    // 설정된 풀 크기
+	LowWater int    // 설정된 low-water 값
+	Hits     uint64 // 풀에서 바로 가져간 CreateTestDB 호출 수
+	Misses   uint64 // 풀이 비어 있거나 템플릿이 달라 직접 생성한 CreateTestDB 호출 수
+	Created  uint64 // 풀이 백그라운드에서 생성한 데이터베이스 수
+	Recycled uint64 // 반환된 뒤 백그라운드에서 삭제된 데이터베이스 수
+	Expired  uint64 // 최대 유휴 시간이 지나거나 템플릿이 바뀌어 삭제된 데이터베이스 수
+	Failures uint64 // 백그라운드 생성 또는 삭제에 실패한 횟수
+}
+
+// pooledDB 풀에서 대기 중인 데이터베이스입니다.
+type pooledDB struct {
+	name     string
+	template string
+	since    time.Time
+}
+
+// databasePool 테스트 데이터베이스를 미리 만들어 두고, 반환된 데이터베이스를 백그라운드에서 삭제합니다.
+// 백그라운드 작업은 serverMutex를 사용하지 않으므로 CreateTestDB와 경쟁하지 않습니다.
+type databasePool struct {
+	cfg    poolConfig
+	logger *zap.Logger
+
+	mu       sync.Mutex
+	ready    []pooledDB
+	template string // 새로 만들 데이터베이스의 템플릿
+	stats    PoolStats
+
+	wake   chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// EnableDBPool 테스트 데이터베이스 풀을 시작합니다.
+// 풀은 지정한 수의 데이터베이스를 백그라운드에서 미리 만들어 두고, CreateTestDB는 기다리지 않고 그중 하나를 가져갑니다.
+// 반환된 데이터베이스는 Close에서 기다리지 않고 백그라운드에서 삭제됩니다.
+// 서버가 실행 중이어야 하며, 풀은 DisableDBPool이나 StopPostgres에서 정리됩니다. RunTests에서는 WithDBPool을 사용할 수 있습니다.
+//
+// 사용 예시:
+//
+//	err := pgtestkit.EnableDBPool(pgtestkit.WithPoolSize(8), pgtestkit.WithPoolMaxIdle(time.Minute))
+func EnableDBPool(opts ...PoolOption) error {
+	cfg, err := newPoolConfig(opts)
+	if err != nil {
+		logError("Invalid pool configuration", err)
+		return err
+	}
+
+	serverMutex.Lock()
+	defer serverMutex.Unlock()
+
+	if !serverStarted || serverStopped {
+		err := serverNotRunningError()
+		logError("Cannot enable database pool", err)
+		return err
+	}
+	if dbPool != nil {
+		return fmt.Errorf("database pool is already enabled")
+	}
+
+	startDBPool(cfg)
+	return nil
+}
+
+// DisableDBPool 테스트 데이터베이스 풀을 멈추고, 진행 중인 백그라운드 작업이 끝나길 기다린 뒤
+// 풀에 남은 데이터베이스를 삭제합니다. 풀이 없으면 아무것도 하지 않으며, 이후 CreateTestDB는 데이터베이스를 직접 만듭니다.
+//
+// 사용 예시:
+//
+//	if err := pgtestkit.EnableDBPool(pgtestkit.WithPoolSize(4)); err != nil {
+//		t.Fatal(err)
+//	}
+//	t.Cleanup(func() {
+//		if err := pgtestkit.DisableDBPool(); err != nil {
+//			t.Error(err)
+//		}
+//	})
+func DisableDBPool() error {
+	return DisableDBPoolContext(context.Background())
+}
+
+// DisableDBPoolContext 컨텍스트를 지원하는 DisableDBPool입니다.
+func DisableDBPoolContext(ctx context.Context) error {
+	serverMutex.Lock()
+	defer serverMutex.Unlock()
+
+	if dbPool == nil {
+		return nil
+	}
+	errs := dbPool.close(ctx)
+	dbPool = nil
+
+	if len(errs) > 0 {
+		err := fmt.Errorf("%d error(s) occurred while disabling database pool: %w", len(errs), errors.Join(errs...))
+		logError("Errors occurred while disabling database pool", err)
+		return err
+	}
+	return nil
+}
+
+// WithDBPool 서버가 시작되면 테스트 데이터베이스 풀을 함께 시작합니다. EnableDBPool의 ServerOption 버전입니다.
+func WithDBPool(opts ...PoolOption) ServerOption {
+	return func(c *serverConfig) {
+		c.pool = append([]PoolOption{}, opts...)
+		c.poolEnabled = true
+	}
+}
+
+// DBPoolStats 테스트 데이터베이스 풀의 현재 통계를 반환합니다. 풀이 없으면 빈 값을 반환합니다.
+func DBPoolStats() PoolStats {
+	serverMutex.Lock()
+	p := dbPool
+	serverMutex.Unlock()
+
+	if p == nil {
+		return PoolStats{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	stats := p.stats
+	stats.Ready = len(p.ready)
+	stats.Size = p.cfg.size
+	stats.LowWater = p.cfg.lowWater
+	return stats
+}
+
+// startDBPool 풀을 만들고 백그라운드 작업을 시작합니다. 호출자는 serverMutex를 보유하고 있어야 합니다.
+func startDBPool(cfg poolConfig) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &databasePool{
+		cfg:      cfg,
+		logger:   getLogger().With(zap.String("component", "pool")),
+		template: defaultTemplate,
+		wake:     make(chan struct{}, 1),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	if cfg.useTemplate {
+		p.template = cfg.template
+	}
+
+	p.logger.Info("Starting database pool",
+		zap.Int("size", cfg.size),
+		zap.Int("low_water", cfg.lowWater),
+		zap.Duration("max_idle", cfg.maxIdle),
+		zap.String("template", p.template))
+
+	dbPool = p
+	p.wg.Add(1)
+	go p.run()
+	p.notify()
+}
+
+// notify 백그라운드 작업에 풀을 다시 채우도록 알립니다.
+func (p *databasePool) notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run 요청이 있을 때 풀을 채우고, 주기적으로 유휴 데이터베이스를 정리합니다.
+func (p *databasePool) run() {
+	defer p.wg.Done()
+
+	var tick <-chan time.Time
+	if p.cfg.maxIdle > 0 {
+		ticker := time.NewTicker(max(p.cfg.maxIdle/2, minPoolExpireInterval))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-p.ctx.Done():
+			return
+		case <-p.wake:
+			p.fill()
+		case <-tick:
+			p.expire()
+		}
+	}
+}
+
+// fill 준비된 데이터베이스가 low-water보다 적으면 설정된 크기까지 채웁니다.
+func (p *databasePool) fill() {
+	p.mu.Lock()
+	if len(p.ready) >= p.cfg.lowWater && len(p.ready) > 0 {
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+
+	for p.ctx.Err() == nil {
+		p.mu.Lock()
+		if len(p.ready) >= p.cfg.size {
+			p.mu.Unlock()
+			return
+		}
+		template := p.template
+		p.mu.Unlock()
+
+		name := generateTestDBName("pool")
+		if err := createDatabase(p.ctx, name, template); err != nil {
+			if p.ctx.Err() != nil {
+				return
+			}
+			p.mu.Lock()
+			p.stats.Failures++
+			p.mu.Unlock()
+			p.logger.Warn("Failed to create pooled database", zap.Error(err))
+			return
+		}
+
+		p.mu.Lock()
+		p.ready = append(p.ready, pooledDB{name: name, template: template, since: time.Now()})
+		p.stats.Created++
+		p.mu.Unlock()
+	}
+}
+
+// expire 최대 유휴 시간이 지난 데이터베이스를 삭제합니다.
+func (p *databasePool) expire() {
+	cutoff := time.Now().Add(-p.cfg.maxIdle)
+
+	p.mu.Lock()
+	var expired []string
+	kept := p.ready[:0]
+	for _, db := range p.ready {
+		if db.since.Before(cutoff) {
+			expired = append(expired, db.name)
+		} else {
+			kept = append(kept, db)
+		}
+	}
+	p.ready = kept
+	p.stats.Expired += uint64(len(expired))
+	p.mu.Unlock()
+
+	for _, name := range expired {
+		p.logger.Debug("Dropping idle pooled database", zap.String("database", name))
+		p.drop(name, false)
+	}
+}
+
+// take 지정한 템플릿으로 만든 데이터베이스를 풀에서 꺼냅니다. 풀이 비었으면 false를 반환합니다.
+func (p *databasePool) take(template string) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	defer p.notify()
+
+	for i, db := range p.ready {
+		if db.template == template {
+			p.ready = append(p.ready[:i], p.ready[i+1:]...)
+			p.stats.Hits++
+			return db.name, true
+		}
+	}
+	p.stats.Misses++
+	return "", false
+}
+
+// setTemplate 기본 템플릿이 바뀌면 다른 템플릿으로 만든 데이터베이스를 정리하고 풀을 다시 채웁니다.
+func (p *databasePool) setTemplate(template string) {
+	if p.cfg.useTemplate {
+		return
+	}
+
+	p.mu.Lock()
+	p.template = template
+	var stale []string
+	kept := p.ready[:0]
+	for _, db := range p.ready {
+		if db.template == template {
+			kept = append(kept, db)
+		} else {
+			stale = append(stale, db.name)
+		}
+	}
+	p.ready = kept
+	p.stats.Expired += uint64(len(stale))
+	p.mu.Unlock()
+
+	for _, name := range stale {
+		p.recycle(name)
+	}
+	p.notify()
+}
+
+// recycle 반환된 데이터베이스를 백그라운드에서 삭제하고 풀을 다시 채우도록 알립니다.
+func (p *databasePool) recycle(name string) {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		p.drop(name, true)
+		p.notify()
+	}()
+}
+
+// drop 데이터베이스를 삭제하고 통계를 기록합니다.
+func (p *databasePool) drop(name string, recycled bool) {
+	err := dropDatabase(context.Background(), name)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err != nil {
+		p.stats.Failures++
+		p.logger.Warn("Failed to drop pooled database", zap.String("database", name), zap.Error(err))
+		return
+	}
+	if recycled {
+		p.stats.Recycled++
+	}
+}
+
+// close 백그라운드 작업을 멈추고, 진행 중인 삭제가 끝나길 기다린 뒤 남은 데이터베이스를 삭제합니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func (p *databasePool) close(ctx context.Context) []error {
+	p.cancel()
+	p.wg.Wait()
+
+	p.mu.Lock()
+	ready := p.ready
+	p.ready = nil
+	p.mu.Unlock()
+
+	var errs []error
+	for _, db := range ready {
+		if err := dropDatabase(ctx, db.name); err != nil {
+			errs = append(errs, fmt.Errorf("failed to drop pooled database %s: %w", db.name, err))
+		}
+	}
+
+	p.logger.Info("Stopped database pool",
+		zap.Uint64("hits", p.stats.Hits),
+		zap.Uint64("misses", p.stats.Misses))
+	return errs
+}
+
+// renameDatabase 데이터베이스 이름을 변경합니다. 풀에서 꺼낸 데이터베이스에 테스트 이름을 붙일 때 사용합니다.
+func renameDatabase(ctx context.Context, from, to string) error {
+	if baseDBClient == nil {
+		return fmt.Errorf("base database client is not initialized")
+	}
+	if _, err := baseDBClient.ExecContext(ctx,
+		fmt.Sprintf("ALTER DATABASE %s RENAME TO %s", quoteIdentifier(from), quoteIdentifier(to))); err != nil {
+		return fmt.Errorf("failed to rename database %s to %s: %w", from, to, err)
+	}
+	return nil
+}
+
+// takePooledDB 풀에서 옵션의 템플릿으로 만든 데이터베이스를 꺼내 name으로 이름을 바꿉니다.
+// 이름을 바꾸지 못하면 풀의 이름을 그대로 사용합니다. 호출자는 serverMutex를 보유하고 있어야 합니다.
+func takePooledDB(ctx context.Context, options dbOptions, name string, logger *zap.Logger) (string, bool) {
+	if dbPool == nil {
+		return "", false
+	}
+
+	pooled, ok := dbPool.take(options.template)
+	if !ok {
+		logger.Debug("Database pool is empty, creating database synchronously")
+		return "", false
+	}
+
+	if err := renameDatabase(ctx, pooled, name); err != nil {
+		logger.Warn("Failed to rename pooled database, keeping its name", zap.Error(err))
+		return pooled, true
+	}
+	return name, true
+}
+
+// recycle 풀이 활성화되어 있으면 데이터베이스를 풀에 넘겨 백그라운드에서 삭제합니다.
+func (c *DBClient) recycle() bool {
+	serverMutex.Lock()
+	defer serverMutex.Unlock()
+
+	if dbPool == nil {
+		return false
+	}
+	dbPool.recycle(c.DBName)
+	return true
+}
@@ -0,0 +1,107 @@
+package pgtestkit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestNewPoolConfig(t *testing.T) {
+	cfg, err := newPoolConfig(nil)
+	if err != nil {
+		t.Fatalf("newPoolConfig() error = %v", err)
+	}
+	if cfg.size != DefaultPoolSize || cfg.lowWater != (DefaultPoolSize+1)/2 {
+		t.Errorf("newPoolConfig() = size %d, low-water %d", cfg.size, cfg.lowWater)
+	}
+
+	invalid := map[string][]PoolOption{
+		"zero size":          {WithPoolSize(0)},
+		"low-water too high": {WithPoolSize(2), WithPoolLowWater(3)},
+		"negative low-water": {WithPoolLowWater(-2)},
+		"negative max idle":  {WithPoolMaxIdle(-time.Second)},
+	}
+	for name, opts := range invalid {
+		if _, err := newPoolConfig(opts); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestDatabasePoolTake(t *testing.T) {
+	p := &databasePool{
+		wake: make(chan struct{}, 1),
+		ready: []pooledDB{
+			{name: "db_a", template: "template0"},
+			{name: "db_b", template: "golden"},
+		},
+	}
+
+	if name, ok := p.take("golden"); !ok || name != "db_b" {
+		t.Errorf("take(golden) = %q, %v", name, ok)
+	}
+	if _, ok := p.take("golden"); ok {
+		t.Error("Expected miss for exhausted template")
+	}
+	if name, ok := p.take("template0"); !ok || name != "db_a" {
+		t.Errorf("take(template0) = %q, %v", name, ok)
+	}
+
+	if p.stats.Hits != 2 || p.stats.Misses != 1 {
+		t.Errorf("stats = %+v, want 2 hits and 1 miss", p.stats)
+	}
+	select {
+	case <-p.wake:
+	default:
+		t.Error("Expected take to wake the pool")
+	}
+}
+
+func TestDisableDBPool(t *testing.T) {
+	if err := DisableDBPool(); err != nil {
+		t.Fatalf("DisableDBPool() without a pool error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &databasePool{
+		logger: getLogger(),
+		wake:   make(chan struct{}, 1),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	p.wg.Add(1)
+	go p.run()
+
+	serverMutex.Lock()
+	dbPool = p
+	serverMutex.Unlock()
+
+	if err := DisableDBPool(); err != nil {
+		t.Fatalf("DisableDBPool() error = %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Error("Expected the background worker to be stopped")
+	}
+	if stats := DBPoolStats(); stats != (PoolStats{}) {
+		t.Errorf("Expected no pool after DisableDBPool, got %+v", stats)
+	}
+}
+
+func TestDatabasePoolTinyMaxIdle(t *testing.T) {
+	// maxIdle/2가 0이 되어도 타이머를 만들 때 패닉이 나지 않아야 함
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &databasePool{
+		cfg:    poolConfig{maxIdle: time.Nanosecond},
+		logger: getLogger(),
+		wake:   make(chan struct{}, 1),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+	p.wg.Add(1)
+	go p.run()
+
+	time.Sleep(5 * minPoolExpireInterval)
+	if errs := p.close(context.Background()); len(errs) > 0 {
+		t.Fatalf("close() errors = %v", errs)
+	}
+}
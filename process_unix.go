@@ -0,0 +1,18 @@
+//go:build !windows
+
+package pgtestkit
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive 지정한 PID의 프로세스가 실행 중인지 확인합니다.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	// EPERM은 프로세스가 존재하지만 신호를 보낼 권한이 없다는 뜻
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
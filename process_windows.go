@@ -0,0 +1,21 @@
+//go:build windows
+
+package pgtestkit
+
+import (
+	"os"
+)
+
+// processAlive 지정한 PID의 프로세스가 실행 중인지 확인합니다.
+// Windows에서 os.FindProcess는 프로세스 핸들을 열 수 있을 때만 성공합니다.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	_ = p.Release()
+	return true
+}
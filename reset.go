@@ -0,0 +1,464 @@
+package pgtestkit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// 초기화에서 기본으로 제외하는 마이그레이션 도구의 버전 테이블
+// (pgtestkit, golang-migrate, goose, Atlas 순)
+var defaultResetExcludes = []string{DefaultMigrationsTable, "schema_migrations", "goose_db_version", "atlas_schema_revisions"}
+
+// MigrationTables 초기화에서 기본으로 제외하는 마이그레이션 도구의 버전 테이블 이름을 반환합니다.
+// pgtestkit_migrations, golang-migrate의 schema_migrations, goose의 goose_db_version, Atlas의 atlas_schema_revisions입니다.
+// 직접 만든 커넥터의 Reset에서 비우지 않을 테이블로 사용하세요.
+func MigrationTables() []string {
+	return slices.Clone(defaultResetExcludes)
+}
+
+// ResetStrategy 테스트 데이터베이스를 초기 상태로 되돌리는 방법입니다.
+// WithResetStrategy로 지정하면 커넥터의 Reset 대신 사용되므로, 어떤 커넥터와도 함께 사용할 수 있습니다.
+type ResetStrategy interface {
+	// Reset c의 데이터베이스(스키마 격리 모드에서는 테스트 스키마)를 초기 상태로 되돌립니다.
+	Reset(ctx context.Context, c *DBClient) error
+}
+
+// ResetStrategyFunc 함수를 ResetStrategy로 사용할 수 있게 합니다.
+type ResetStrategyFunc func(ctx context.Context, c *DBClient) error
+
+// Reset f를 호출합니다.
+func (f ResetStrategyFunc) Reset(ctx context.Context, c *DBClient) error {
+	return f(ctx, c)
+}
+
+// ResetOption 초기화 대상을 변경하는 옵션입니다.
+type ResetOption func(*resetConfig)
+
+// resetConfig 초기화할 스키마와 테이블 설정입니다.
+type resetConfig struct {
+	schemas  []string
+	include  []string
+	exclude  []string
+	excluded bool // WithResetExcludeTables로 기본 제외 목록을 대체했는지 여부
+}
+
+// WithResetSchemas 초기화할 스키마를 지정합니다.
+// 기본값은 public이며, 스키마 격리 모드에서는 테스트 스키마입니다.
+func WithResetSchemas(schemas ...string) ResetOption {
+	return func(c *resetConfig) {
+		c.schemas = append(c.schemas, schemas...)
+	}
+}
+
+// WithResetTables 지정한 테이블만 초기화합니다.
+// 테이블은 "name" 또는 "schema.name" 형식이며, 스키마를 생략하면 모든 대상 스키마에서 찾습니다.
+func WithResetTables(tables ...string) ResetOption {
+	return func(c *resetConfig) {
+		c.include = append(c.include, tables...)
+	}
+}
+
+// WithResetExcludeTables 지정한 테이블을 초기화하지 않습니다. 형식은 WithResetTables와 같습니다.
+// 기본으로 제외되는 마이그레이션 버전 테이블(pgtestkit_migrations, schema_migrations, goose_db_version,
+// atlas_schema_revisions)을 대체하므로, 필요하면 함께 지정하세요.
+func WithResetExcludeTables(tables ...string) ResetOption {
+	return func(c *resetConfig) {
+		c.exclude = append(c.exclude, tables...)
+		c.excluded = true
+	}
+}
+
+// newResetConfig 옵션을 적용하고 기본 제외 목록을 채웁니다.
+func newResetConfig(opts []ResetOption) resetConfig {
+	var cfg resetConfig
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if !cfg.excluded {
+		cfg.exclude = defaultResetExcludes
+	}
+	return cfg
+}
+
+// schemasFor c에서 초기화할 스키마 목록을 반환합니다.
+func (cfg resetConfig) schemasFor(c *DBClient) []string {
+	switch {
+	case len(cfg.schemas) > 0:
+		return cfg.schemas
+	case c.Schema != "":
+		return []string{c.Schema}
+	default:
+		return []string{"public"}
+	}
+}
+
+// selects schema.name 테이블이 포함 목록에 있고 제외 목록에 없는지 확인합니다.
+func (cfg resetConfig) selects(schema, name string) bool {
+	if len(cfg.include) > 0 && !matchesTable(cfg.include, schema, name) {
+		return false
+	}
+	return !matchesTable(cfg.exclude, schema, name)
+}
+
+// matchesTable schema.name 테이블이 "name" 또는 "schema.name" 목록에 있는지 확인합니다.
+func matchesTable(list []string, schema, name string) bool {
+	for _, entry := range list {
+		if s, n, ok := strings.Cut(entry, "."); ok {
+			if s == schema && n == name {
+				return true
+			}
+		} else if entry == name {
+			return true
+		}
+	}
+	return false
+}
+
+// WithResetStrategy 커넥터의 Reset 대신 strategy로 데이터베이스를 초기화합니다.
+// TestHelper.ResetDB가 strategy를 사용하며, 새로 만든 데이터베이스는 이미 초기 상태이므로
+// 생성 시에는 초기화하지 않습니다. 커넥터의 Reset은 호출되지 않습니다.
+// 트랜잭션 격리 모드에서는 Reset이 항상 테스트 시작 시점으로 롤백하므로 사용할 수 없습니다.
+//
+// 사용 예시:
+//
+//	dbClient, helper := pgtestkit.New(t, &SQLConnector{},
+//		pgtestkit.WithResetStrategy(pgtestkit.TruncateTables(pgtestkit.WithResetExcludeTables("countries"))))
+func WithResetStrategy(strategy ResetStrategy) Option {
+	return func(o *dbOptions) {
+		o.resetStrategy = strategy
+	}
+}
+
+// resetPreparer 테스트 데이터베이스를 만든 직후 준비 작업이 필요한 초기화 전략입니다.
+type resetPreparer interface {
+	// prepare 새로 만든 c의 데이터베이스를 전략에 맞게 준비합니다.
+	prepare(ctx context.Context, c *DBClient) error
+}
+
+// initialReset 새로 만든 테스트 데이터베이스를 초기화합니다.
+// 초기화 전략이 지정된 경우 데이터베이스는 이미 초기 상태이므로 커넥터의 Reset 대신 전략의 준비 작업만 수행합니다.
+func initialReset(ctx context.Context, c *DBClient, logger *zap.Logger) error {
+	if c.resetStrategy == nil {
+		return resetWithRetry(ctx, c.connector, logger)
+	}
+	if p, ok := c.resetStrategy.(resetPreparer); ok {
+		logger.Debug("Preparing reset strategy")
+		return p.prepare(ctx, c)
+	}
+	logger.Debug("Skipping connector reset, reset strategy is configured")
+	return nil
+}
+
+// TruncateTables 대상 스키마의 모든 테이블을 TRUNCATE ... RESTART IDENTITY로 한 번에 비우는 전략을 반환합니다.
+// 가장 빠르지만 테이블마다 ACCESS EXCLUSIVE 잠금을 잡습니다. CASCADE는 사용하지 않으므로,
+// 제외한 테이블이 비우는 테이블을 참조하면 오류가 발생합니다.
+func TruncateTables(opts ...ResetOption) ResetStrategy {
+	cfg := newResetConfig(opts)
+	return ResetStrategyFunc(func(ctx context.Context, c *DBClient) error {
+		return withResetTx(ctx, c, func(tx *sql.Tx) error {
+			tables, err := listResetTables(ctx, tx, cfg, c)
+			if err != nil {
+				return err
+			}
+			if len(tables) == 0 {
+				return nil
+			}
+			quoted := make([]string, len(tables))
+			for i, table := range tables {
+				quoted[i] = table.quoted()
+			}
+			if _, err := tx.ExecContext(ctx, "TRUNCATE TABLE "+strings.Join(quoted, ", ")+" RESTART IDENTITY"); err != nil {
+				return fmt.Errorf("failed to truncate tables: %w", err)
+			}
+			return nil
+		})
+	})
+}
+
+// DeleteTables 외래 키를 따라 참조하는 테이블부터 DELETE로 비우고, 테이블이 소유한 시퀀스를 처음 값으로 되돌리는 전략을 반환합니다.
+// TRUNCATE보다 약한 잠금을 사용하므로 작은 테이블이 많거나 다른 세션이 같은 테이블을 읽을 때 유리합니다.
+// 서로를 참조하는 테이블은 제약 조건이 DEFERRABLE이어야 합니다.
+func DeleteTables(opts ...ResetOption) ResetStrategy {
+	cfg := newResetConfig(opts)
+	return ResetStrategyFunc(func(ctx context.Context, c *DBClient) error {
+		return withResetTx(ctx, c, func(tx *sql.Tx) error {
+			tables, err := listResetTables(ctx, tx, cfg, c)
+			if err != nil {
+				return err
+			}
+			if len(tables) == 0 {
+				return nil
+			}
+			refs, err := listForeignKeys(ctx, tx)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, "SET CONSTRAINTS ALL DEFERRED"); err != nil {
+				return fmt.Errorf("failed to defer constraints: %w", err)
+			}
+			for _, table := range deleteOrder(tables, refs) {
+				if _, err := tx.ExecContext(ctx, "DELETE FROM "+table.quoted()); err != nil {
+					return fmt.Errorf("failed to delete from %s: %w", table, err)
+				}
+			}
+			return restartOwnedSequences(ctx, tx, tables)
+		})
+	})
+}
+
+// RecreateSchemas 대상 스키마를 DROP SCHEMA ... CASCADE로 삭제하고 다시 만든 뒤,
+// WithMigrations나 WithMigrator로 지정한 마이그레이션을 다시 적용하는 전략을 반환합니다.
+// 테이블 목록 옵션은 무시됩니다. 템플릿에서 복제한 데이터도 사라지므로, 이를 유지하려면 RecreateDatabase를 사용하세요.
+func RecreateSchemas(opts ...ResetOption) ResetStrategy {
+	cfg := newResetConfig(opts)
+	return ResetStrategyFunc(func(ctx context.Context, c *DBClient) error {
+		err := withResetTx(ctx, c, func(tx *sql.Tx) error {
+			for _, schema := range cfg.schemasFor(c) {
+				if _, err := tx.ExecContext(ctx, "DROP SCHEMA IF EXISTS "+quoteIdentifier(schema)+" CASCADE"); err != nil {
+					return fmt.Errorf("failed to drop schema %s: %w", schema, err)
+				}
+				if _, err := tx.ExecContext(ctx, "CREATE SCHEMA "+quoteIdentifier(schema)); err != nil {
+					return fmt.Errorf("failed to create schema %s: %w", schema, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if c.migrator != nil {
+			if err := c.migrator.Migrate(ctx, c.ConnectionString); err != nil {
+				return fmt.Errorf("failed to migrate recreated schemas: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// RecreateDatabase 테스트 데이터베이스를 삭제하고 생성할 때와 같은 템플릿으로 다시 만드는 전략을 반환합니다.
+// 마이그레이션이 지정되어 있으면 다시 적용합니다. 템플릿의 데이터까지 그대로 되돌리지만 가장 느립니다.
+//
+// 데이터베이스를 삭제하려면 연결을 모두 끊어야 하므로 커넥터를 닫고 다시 연결하며, DBClient.Client가 새 클라이언트로 바뀝니다.
+// Reset 전에 꺼내 둔 클라이언트는 더 이상 사용할 수 없으니 Reset 후에 다시 읽으세요.
+// 여러 테스트가 데이터베이스를 공유하는 스키마 격리 모드에서는 사용할 수 없습니다.
+func RecreateDatabase() ResetStrategy {
+	return ResetStrategyFunc(recreateDatabase)
+}
+
+// recreateDatabase RecreateDatabase 전략을 수행합니다.
+func recreateDatabase(ctx context.Context, c *DBClient) error {
+	if c.schemaDB != nil {
+		return fmt.Errorf("RecreateDatabase cannot be used with WithSchemaIsolation, use RecreateSchemas instead")
+	}
+	if c.connector == nil {
+		return fmt.Errorf("database connector is nil")
+	}
+	logger := c.logger().With(zap.String("template", c.template))
+
+	logger.Debug("Closing connector before recreating database")
+	if err := c.connector.Close(); err != nil {
+		return fmt.Errorf("failed to close connector: %w", err)
+	}
+
+	serverMutex.Lock()
+	err := dropDatabase(ctx, c.DBName)
+	if err == nil {
+		err = createDatabase(ctx, c.DBName, c.template)
+	}
+	serverMutex.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to recreate database: %w", err)
+	}
+
+	if c.migrator != nil {
+		if err := c.migrator.Migrate(ctx, c.ConnectionString); err != nil {
+			return fmt.Errorf("failed to migrate recreated database: %w", err)
+		}
+	}
+
+	client, err := connectWithRetry(ctx, c.connector, c.ConnectionString, logger)
+	if err != nil {
+		return fmt.Errorf("failed to reconnect to recreated database: %w", err)
+	}
+	c.Client = client
+	return nil
+}
+
+// resetTable 초기화할 테이블입니다.
+type resetTable struct {
+	schema string
+	name   string
+}
+
+// String schema.name 형식으로 반환합니다.
+func (t resetTable) String() string {
+	return t.schema + "." + t.name
+}
+
+// quoted 식별자를 이스케이프한 schema.name을 반환합니다.
+func (t resetTable) quoted() string {
+	return quoteIdentifier(t.schema) + "." + quoteIdentifier(t.name)
+}
+
+// withResetTx c의 데이터베이스에 별도로 연결하여 fn을 트랜잭션으로 실행합니다.
+func withResetTx(ctx context.Context, c *DBClient, fn func(tx *sql.Tx) error) error {
+	if c.ConnectionString == "" {
+		return fmt.Errorf("database connection string is empty")
+	}
+	db, err := sql.Open("pgx", c.ConnectionString)
+	if err != nil {
+		return fmt.Errorf("failed to open reset connection: %w", err)
+	}
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin reset transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit reset transaction: %w", err)
+	}
+	return nil
+}
+
+// listResetTables 대상 스키마에서 cfg가 선택한 일반 테이블과 파티션 테이블을 이름순으로 반환합니다.
+// 파티션은 부모 테이블과 함께 비워지므로 제외하며, 확장 기능이 소유한 테이블과 변경 기록 테이블도 제외합니다.
+func listResetTables(ctx context.Context, tx *sql.Tx, cfg resetConfig, c *DBClient) ([]resetTable, error) {
+	rows, err := queryStrings(ctx, tx, `
+		SELECT n.nspname, c.relname
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p')
+			AND NOT c.relispartition
+			AND n.nspname = ANY($1)
+			AND c.relname <> $2
+			AND NOT EXISTS (SELECT 1 FROM pg_depend d
+				WHERE d.classid = 'pg_class'::regclass AND d.objid = c.oid AND d.deptype = 'e')
+		ORDER BY 1, 2`, cfg.schemasFor(c), dirtyTablesTable)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tables: %w", err)
+	}
+
+	tables := make([]resetTable, 0, len(rows))
+	for _, row := range rows {
+		if cfg.selects(row[0], row[1]) {
+			tables = append(tables, resetTable{schema: row[0], name: row[1]})
+		}
+	}
+	return tables, nil
+}
+
+// listForeignKeys 외래 키로 연결된 (참조하는 테이블, 참조되는 테이블) 쌍을 반환합니다.
+func listForeignKeys(ctx context.Context, tx *sql.Tx) ([][2]resetTable, error) {
+	rows, err := queryStrings(ctx, tx, `
+		SELECT cn.nspname, c.relname, rn.nspname, r.relname
+		FROM pg_constraint k
+		JOIN pg_class c ON c.oid = k.conrelid
+		JOIN pg_namespace cn ON cn.oid = c.relnamespace
+		JOIN pg_class r ON r.oid = k.confrelid
+		JOIN pg_namespace rn ON rn.oid = r.relnamespace
+		WHERE k.contype = 'f' AND k.conparentid = 0`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list foreign keys: %w", err)
+	}
+
+	refs := make([][2]resetTable, 0, len(rows))
+	for _, row := range rows {
+		refs = append(refs, [2]resetTable{{row[0], row[1]}, {row[2], row[3]}})
+	}
+	return refs, nil
+}
+
+// deleteOrder 다른 테이블이 참조하는 테이블이 참조하는 테이블보다 나중에 오도록 tables를 정렬합니다.
+// 순서가 정해지지 않는 테이블은 이름순이며, 순환 참조에 포함된 테이블은 마지막에 이름순으로 둡니다.
+func deleteOrder(tables []resetTable, refs [][2]resetTable) []resetTable {
+	// referencedBy[t]: t를 참조하는 (아직 비우지 않은) 테이블 수
+	referencedBy := make(map[resetTable]int, len(tables))
+	referencing := make(map[resetTable][]resetTable)
+	for _, t := range tables {
+		referencedBy[t] = 0
+	}
+	for _, ref := range refs {
+		from, to := ref[0], ref[1]
+		if from == to {
+			continue // 자기 참조는 한 문장으로 함께 지워짐
+		}
+		if _, ok := referencedBy[from]; !ok {
+			continue
+		}
+		if _, ok := referencedBy[to]; !ok {
+			continue
+		}
+		referencedBy[to]++
+		referencing[from] = append(referencing[from], to)
+	}
+
+	order := make([]resetTable, 0, len(tables))
+	done := make(map[resetTable]bool, len(tables))
+	for len(order) < len(tables) {
+		progressed := false
+		for _, t := range tables {
+			if done[t] || referencedBy[t] > 0 {
+				continue
+			}
+			done[t] = true
+			order = append(order, t)
+			for _, to := range referencing[t] {
+				referencedBy[to]--
+			}
+			progressed = true
+			break
+		}
+		if !progressed {
+			// 순환 참조: 남은 테이블은 지연된 제약 조건에 맡김
+			for _, t := range tables {
+				if !done[t] {
+					order = append(order, t)
+				}
+			}
+			break
+		}
+	}
+	return order
+}
+
+// restartOwnedSequences tables의 컬럼이 소유한 시퀀스(serial, identity)를 처음 값으로 되돌립니다.
+func restartOwnedSequences(ctx context.Context, tx *sql.Tx, tables []resetTable) error {
+	names := make([]string, len(tables))
+	for i, table := range tables {
+		names[i] = table.quoted()
+	}
+	rows, err := queryStrings(ctx, tx, `
+		SELECT sn.nspname, s.relname
+		FROM pg_depend d
+		JOIN pg_class s ON s.oid = d.objid AND s.relkind = 'S'
+		JOIN pg_namespace sn ON sn.oid = s.relnamespace
+		WHERE d.classid = 'pg_class'::regclass
+			AND d.refclassid = 'pg_class'::regclass
+			AND d.deptype IN ('a', 'i')
+			AND d.refobjid = ANY($1::text[]::regclass[])
+		ORDER BY 1, 2`, names)
+	if err != nil {
+		return fmt.Errorf("failed to list owned sequences: %w", err)
+	}
+
+	for _, row := range rows {
+		seq := resetTable{schema: row[0], name: row[1]}
+		if _, err := tx.ExecContext(ctx, "ALTER SEQUENCE "+seq.quoted()+" RESTART"); err != nil {
+			return fmt.Errorf("failed to restart sequence %s: %w", seq, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package pgtestkit
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestResetConfigSelects(t *testing.T) {
+	cfg := newResetConfig(nil)
+	if cfg.selects("public", DefaultMigrationsTable) {
+		t.Error("migrations table should be excluded by default")
+	}
+	if !cfg.selects("public", "users") {
+		t.Error("users should be selected by default")
+	}
+
+	cfg = newResetConfig([]ResetOption{
+		WithResetTables("users", "audit.events"),
+		WithResetExcludeTables("archive.users"),
+	})
+	tests := []struct {
+		schema, name string
+		want         bool
+	}{
+		{"public", "users", true},
+		{"archive", "users", false},
+		{"audit", "events", true},
+		{"public", "events", false},
+		{"public", DefaultMigrationsTable, false},
+	}
+	for _, tt := range tests {
+		if got := cfg.selects(tt.schema, tt.name); got != tt.want {
+			t.Errorf("selects(%q, %q) = %v, want %v", tt.schema, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMigrationTables(t *testing.T) {
+	tables := MigrationTables()
+	cfg := newResetConfig(nil)
+	for _, table := range []string{DefaultMigrationsTable, "schema_migrations", "goose_db_version"} {
+		if !slices.Contains(tables, table) {
+			t.Errorf("Expected %s in MigrationTables()", table)
+		}
+		if cfg.selects("public", table) {
+			t.Errorf("Expected %s to be excluded by default", table)
+		}
+	}
+
+	// 반환된 목록을 바꿔도 기본 제외 목록은 그대로여야 함
+	tables[0] = "users"
+	if MigrationTables()[0] != DefaultMigrationsTable {
+		t.Error("Expected MigrationTables to return a copy")
+	}
+}
+
+func TestDeleteOrder(t *testing.T) {
+	authors := resetTable{"public", "authors"}
+	books := resetTable{"public", "books"}
+	reviews := resetTable{"public", "reviews"}
+	tags := resetTable{"public", "tags"}
+	other := resetTable{"other", "x"}
+
+	refs := [][2]resetTable{
+		{books, authors},
+		{reviews, books},
+		{reviews, reviews}, // 자기 참조
+		{other, authors},   // 대상이 아닌 테이블
+	}
+	got := deleteOrder([]resetTable{authors, books, reviews, tags}, refs)
+	want := []resetTable{reviews, books, authors, tags}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("deleteOrder() = %v, want %v", got, want)
+	}
+
+	// 순환 참조는 남은 테이블을 이름순으로 둠
+	cycle := [][2]resetTable{{authors, books}, {books, authors}}
+	got = deleteOrder([]resetTable{authors, books, tags}, cycle)
+	want = []resetTable{tags, authors, books}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("deleteOrder() with cycle = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,494 @@
+package pgtestkit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// 스키마 격리 모드의 기본 템플릿 스키마 이름 (serverMutex로 보호)
+	defaultTemplateSchema string
+)
+
+// WithSchemaIsolation 테스트마다 데이터베이스를 만들지 않고, 공유 데이터베이스에 고유한 스키마를 만들어 테스트를 격리합니다.
+// 커넥터에 전달되는 연결 문자열은 search_path가 테스트 스키마로 고정되며, Close는 스키마를 CASCADE로 삭제합니다.
+// 공유 데이터베이스는 템플릿(WithTemplate 또는 SetDefaultTemplate)별로 처음 한 번만 복제됩니다.
+// CREATE DATABASE보다 빠르고 serverMutex를 오래 잡지 않으므로 병렬 테스트에 유리합니다.
+//
+// search_path에는 테스트 스키마만 포함되므로, public 스키마의 확장 기능 등은 스키마를 지정하여 사용해야 합니다.
+func WithSchemaIsolation() Option {
+	return func(o *dbOptions) {
+		o.schemaIsolation = true
+	}
+}
+
+// WithTemplateSchema 스키마 격리 모드에서 지정한 템플릿 스키마를 복제하여 테스트 스키마를 생성합니다.
+// 기본 템플릿 스키마가 설정되어 있어도 이 옵션이 우선하며, 빈 문자열을 전달하면 빈 스키마를 생성합니다.
+func WithTemplateSchema(name string) Option {
+	return func(o *dbOptions) {
+		o.templateSchema = name
+		o.useTemplateSchema = true
+	}
+}
+
+// CreateTemplateSchema 스키마 격리 모드의 공유 데이터베이스에 템플릿 스키마를 만들고 기본 템플릿 스키마로 설정합니다.
+// setup에는 search_path가 템플릿 스키마로 고정된 연결 문자열이 전달되므로 마이그레이션을 그대로 실행할 수 있습니다.
+// 이후 WithSchemaIsolation으로 만드는 테스트 스키마는 이 스키마의 테이블, 데이터, 시퀀스, 외래 키, 뷰,
+// 함수, 트리거를 복제한 상태로 시작합니다. setup이 실패하면 템플릿 스키마는 삭제됩니다.
+//
+// 사용 예시:
+//
+//	err := pgtestkit.CreateTemplateSchema("app_template", func(connString string) error {
+//		return runMigrations(connString)
+//	})
+func CreateTemplateSchema(name string, setup func(connString string) error) error {
+	return CreateTemplateSchemaContext(context.Background(), name, setup)
+}
+
+// CreateTemplateSchemaContext 컨텍스트를 지원하는 CreateTemplateSchema입니다.
+func CreateTemplateSchemaContext(ctx context.Context, name string, setup func(connString string) error) error {
+	if name == "" {
+		return fmt.Errorf("template schema name cannot be empty")
+	}
+
+	logger := getLogger().With(zap.String("template_schema", name))
+	logger.Info("Creating template schema")
+
+	serverMutex.Lock()
+	if !serverStarted || serverStopped {
+		err := serverNotRunningError()
+		serverMutex.Unlock()
+		logError("Cannot create template schema", err)
+		return err
+	}
+	shared, err := getSharedDatabase(ctx, "schema", defaultTemplate, logger)
+	serverMutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if _, err := shared.admin.ExecContext(ctx, "CREATE SCHEMA IF NOT EXISTS "+quoteIdentifier(name)); err != nil {
+		return fmt.Errorf("failed to create template schema: %w", err)
+	}
+
+	// 마이그레이션 등 초기화 작업은 잠금 없이 수행
+	if setup != nil {
+		if err := setup(schemaConnectionString(shared.name, name)); err != nil {
+			logError("Template schema setup failed, dropping template schema", err)
+			if dropErr := dropSchema(context.WithoutCancel(ctx), shared.admin, name); dropErr != nil {
+				logError("Failed to drop template schema after setup error", dropErr)
+			}
+			return fmt.Errorf("failed to set up template schema %s: %w", name, err)
+		}
+	}
+
+	serverMutex.Lock()
+	defaultTemplateSchema = name
+	serverMutex.Unlock()
+
+	logger.Info("Successfully created template schema")
+	return nil
+}
+
+// createSchemaTestDB 스키마 격리 모드의 테스트 데이터베이스 클라이언트를 생성합니다.
+// 스키마 생성과 복제는 serverMutex 없이 수행하므로 병렬로 실행될 수 있습니다.
+func createSchemaTestDB(ctx context.Context, connector DBConnector, options dbOptions, logger *zap.Logger) (*DBClient, error) {
+	serverMutex.Lock()
+	shared, err := getSharedDatabase(ctx, "schema", options.template, logger)
+	serverMutex.Unlock()
+	if err != nil {
+		logError("Failed to prepare shared schema database", err)
+		return nil, err
+	}
+
+	schema := generateTestDBName(options.nameHint)
+	logger = logger.With(zap.String("database", shared.name), zap.String("schema", schema))
+
+	logger.Info("Creating test schema", zap.String("template_schema", options.templateSchema))
+	if options.templateSchema != "" {
+		err = cloneSchema(ctx, shared.admin, options.templateSchema, schema)
+	} else {
+		_, err = shared.admin.ExecContext(ctx, "CREATE SCHEMA "+quoteIdentifier(schema))
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to create test schema: %w", err)
+		logError("Failed to create test schema", err)
+		return nil, err
+	}
+
+	dropOnError := func() {
+		if dropErr := dropSchema(context.WithoutCancel(ctx), shared.admin, schema); dropErr != nil {
+			logError("Failed to clean up test schema", dropErr)
+		}
+	}
+
+	connString := schemaConnectionString(shared.name, schema)
+	if options.migrator != nil {
+		logger.Debug("Applying migrations to test schema")
+		if err := options.migrator.Migrate(ctx, connString); err != nil {
+			logError("Failed to apply migrations, cleaning up", err)
+			dropOnError()
+			return nil, fmt.Errorf("failed to migrate test schema: %w", err)
+		}
+	}
+
+	client, err := connectWithRetry(ctx, connector, connString, logger)
+	if err != nil {
+		logger.Error("Failed to connect to test schema, cleaning up", zap.Error(err))
+		dropOnError()
+		return nil, fmt.Errorf("failed to connect to test schema: %w", err)
+	}
+
+	dbClient := &DBClient{
+		Client:           client,
+		DBName:           shared.name,
+		Schema:           schema,
+		ConnectionString: connString,
+		connector:        connector,
+		tb:               options.tb,
+		keepOnFailure:    *options.keepOnFailure,
+		schemaDB:         shared,
+		template:         options.template,
+		migrator:         options.migrator,
+		resetStrategy:    options.resetStrategy,
+	}
+
+	if err := initialReset(ctx, dbClient, logger); err != nil {
+		logger.Error("Failed to reset test schema, cleaning up", zap.Error(err))
+		if closeErr := connector.Close(); closeErr != nil {
+			logError("Failed to close connector after reset error", closeErr)
+		}
+		dropOnError()
+		return nil, fmt.Errorf("failed to reset test schema: %w", err)
+	}
+
+	logger.Info("Successfully created and initialized test schema")
+	return dbClient, nil
+}
+
+// schemaConnectionString search_path가 지정한 스키마로 고정된 연결 문자열을 생성합니다.
+func schemaConnectionString(dbName, schema string) string {
+	u, err := url.Parse(getConnectionString(dbName))
+	if err != nil {
+		return getConnectionString(dbName)
+	}
+	query := u.Query()
+	query.Set("search_path", quoteIdentifier(schema))
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// dropSchema 스키마와 그 안의 모든 객체를 삭제합니다.
+func dropSchema(ctx context.Context, db *sql.DB, schema string) error {
+	if _, err := db.ExecContext(ctx, "DROP SCHEMA IF EXISTS "+quoteIdentifier(schema)+" CASCADE"); err != nil {
+		return fmt.Errorf("failed to drop schema %s: %w", schema, err)
+	}
+	return nil
+}
+
+// cloneSchema 템플릿 스키마의 객체와 데이터를 새 스키마로 복제합니다.
+// PostgreSQL에는 스키마 복제 기능이 없으므로 시스템 카탈로그를 읽어 객체를 순서대로 다시 만듭니다.
+// 템플릿 스키마에 정의된 타입(enum, domain 등)은 복제하지 않고 템플릿의 것을 그대로 참조합니다.
+// 정의 안의 스키마 이름은 문자열 치환으로 바꾸므로 템플릿 스키마 이름이 리터럴이나 함수 본문에 나타나면 함께 바뀝니다.
+func cloneSchema(ctx context.Context, db *sql.DB, src, dst string) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin schema clone: %w", err)
+	}
+	defer tx.Rollback()
+
+	srcQ, dstQ := quoteIdentifier(src), quoteIdentifier(dst)
+
+	// search_path를 비우면 pg_get_*def 함수가 모든 이름을 스키마로 한정하여 출력하므로 스키마만 바꿔 쓸 수 있음
+	// (출력은 꼭 필요할 때만 따옴표를 사용하므로 quote_ident 형식으로 비교)
+	var srcIdent, dstIdent string
+	if err := tx.QueryRowContext(ctx, `SELECT quote_ident($1), quote_ident($2)`, src, dst).Scan(&srcIdent, &dstIdent); err != nil {
+		return fmt.Errorf("failed to quote schema names: %w", err)
+	}
+	// 정의 문자열 전체를 치환하므로 문자열 리터럴이나 함수 본문 안의 "스키마." 형태 텍스트도 함께 바뀝니다.
+	// 템플릿 스키마 이름이 데이터처럼 쓰이는 경우를 피하려면 다른 곳에 나타나지 않는 이름을 사용해야 합니다.
+	rewrite := func(def string) string {
+		return strings.ReplaceAll(def, srcIdent+".", dstIdent+".")
+	}
+	exec := func(query string, args ...interface{}) error {
+		if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+			return fmt.Errorf("schema clone failed at %q: %w", query, err)
+		}
+		return nil
+	}
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = $1)`, src).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check template schema: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("template schema %s does not exist", src)
+	}
+
+	if err := exec(`SELECT pg_catalog.set_config('search_path', '', true)`); err != nil {
+		return err
+	}
+	if err := exec("CREATE SCHEMA " + dstQ); err != nil {
+		return err
+	}
+
+	// 1. 시퀀스 (identity 컬럼의 시퀀스는 테이블과 함께 생성됨)
+	sequences, err := queryStrings(ctx, tx, `
+		SELECT c.relname, format_type(s.seqtypid, NULL), s.seqstart::text, s.seqincrement::text,
+		       s.seqmin::text, s.seqmax::text, s.seqcache::text, s.seqcycle::text
+		FROM pg_sequence s
+		JOIN pg_class c ON c.oid = s.seqrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1
+		  AND NOT EXISTS (SELECT 1 FROM pg_depend d WHERE d.objid = c.oid AND d.deptype = 'i')
+		ORDER BY c.oid`, src)
+	if err != nil {
+		return err
+	}
+	for _, seq := range sequences {
+		cycle := "NO CYCLE"
+		if seq[7] == "true" {
+			cycle = "CYCLE"
+		}
+		name := quoteIdentifier(seq[0])
+		if err := exec(fmt.Sprintf("CREATE SEQUENCE %s.%s AS %s START WITH %s INCREMENT BY %s MINVALUE %s MAXVALUE %s CACHE %s %s",
+			dstQ, name, seq[1], seq[2], seq[3], seq[4], seq[5], seq[6], cycle)); err != nil {
+			return err
+		}
+		if err := exec(fmt.Sprintf("SELECT setval('%s.%s', last_value, is_called) FROM %s.%s",
+			strings.ReplaceAll(dstQ, "'", "''"), strings.ReplaceAll(name, "'", "''"), srcQ, name)); err != nil {
+			return err
+		}
+	}
+
+	// 2. 함수와 프로시저 (확장 기능에 속한 것은 제외)
+	functions, err := queryStrings(ctx, tx, `
+		SELECT pg_get_functiondef(p.oid)
+		FROM pg_proc p
+		JOIN pg_namespace n ON n.oid = p.pronamespace
+		WHERE n.nspname = $1 AND p.prokind IN ('f', 'p')
+		  AND NOT EXISTS (SELECT 1 FROM pg_depend d WHERE d.objid = p.oid AND d.deptype = 'e')
+		ORDER BY p.oid`, src)
+	if err != nil {
+		return err
+	}
+	for _, fn := range functions {
+		if err := exec(rewrite(fn[0])); err != nil {
+			return err
+		}
+	}
+
+	// 3. 테이블 (컬럼, 기본값, 제약 조건, 인덱스, identity 포함; 외래 키는 데이터 복사 후 추가)
+	tables, err := queryStrings(ctx, tx, `
+		SELECT c.relname
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p') AND NOT c.relispartition
+		ORDER BY c.oid`, src)
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		name := quoteIdentifier(table[0])
+		if err := exec(fmt.Sprintf("CREATE TABLE %s.%s (LIKE %s.%s INCLUDING ALL)", dstQ, name, srcQ, name)); err != nil {
+			return err
+		}
+	}
+
+	// 4. 템플릿 스키마의 시퀀스나 함수를 참조하는 기본값을 새 스키마로 변경
+	defaults, err := queryStrings(ctx, tx, `
+		SELECT c.relname, a.attname, pg_get_expr(d.adbin, d.adrelid)
+		FROM pg_attrdef d
+		JOIN pg_class c ON c.oid = d.adrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_attribute a ON a.attrelid = d.adrelid AND a.attnum = d.adnum
+		WHERE n.nspname = $1 AND a.attgenerated = ''`, src)
+	if err != nil {
+		return err
+	}
+	for _, def := range defaults {
+		if !strings.Contains(def[2], srcIdent+".") {
+			continue
+		}
+		if err := exec(fmt.Sprintf("ALTER TABLE %s.%s ALTER COLUMN %s SET DEFAULT %s",
+			dstQ, quoteIdentifier(def[0]), quoteIdentifier(def[1]), rewrite(def[2]))); err != nil {
+			return err
+		}
+	}
+
+	// 시리얼 컬럼의 시퀀스 소유 관계 (deptype 'a')를 복원하여 테이블이나 컬럼을 삭제하면 시퀀스도 함께 삭제되도록 함
+	owners, err := queryStrings(ctx, tx, `
+		SELECT s.relname, t.relname, a.attname
+		FROM pg_depend d
+		JOIN pg_class s ON s.oid = d.objid AND s.relkind = 'S'
+		JOIN pg_namespace n ON n.oid = s.relnamespace
+		JOIN pg_class t ON t.oid = d.refobjid AND t.relnamespace = s.relnamespace
+		JOIN pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+		WHERE n.nspname = $1 AND d.deptype = 'a'
+		  AND d.classid = 'pg_class'::regclass AND d.refclassid = 'pg_class'::regclass
+		ORDER BY s.oid`, src)
+	if err != nil {
+		return err
+	}
+	for _, owner := range owners {
+		if err := exec(fmt.Sprintf("ALTER SEQUENCE %s.%s OWNED BY %s.%s.%s",
+			dstQ, quoteIdentifier(owner[0]), dstQ, quoteIdentifier(owner[1]), quoteIdentifier(owner[2]))); err != nil {
+			return err
+		}
+	}
+
+	// 5. 데이터 (생성 컬럼 제외, identity 컬럼은 값을 그대로 복사)
+	for _, table := range tables {
+		columns, err := queryStrings(ctx, tx, `
+			SELECT quote_ident(a.attname)
+			FROM pg_attribute a
+			JOIN pg_class c ON c.oid = a.attrelid
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = $1 AND c.relname = $2
+			  AND a.attnum > 0 AND NOT a.attisdropped AND a.attgenerated = ''
+			ORDER BY a.attnum`, src, table[0])
+		if err != nil {
+			return err
+		}
+		if len(columns) == 0 {
+			continue
+		}
+		names := make([]string, len(columns))
+		for i, column := range columns {
+			names[i] = column[0]
+		}
+		list := strings.Join(names, ", ")
+		name := quoteIdentifier(table[0])
+		if err := exec(fmt.Sprintf("INSERT INTO %s.%s (%s) OVERRIDING SYSTEM VALUE SELECT %s FROM %s.%s",
+			dstQ, name, list, list, srcQ, name)); err != nil {
+			return err
+		}
+	}
+
+	// 6. identity 컬럼의 시퀀스 값
+	identities, err := queryStrings(ctx, tx, `
+		SELECT c.relname, a.attname
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p') AND a.attidentity <> '' AND NOT a.attisdropped`, src)
+	if err != nil {
+		return err
+	}
+	for _, identity := range identities {
+		name := quoteIdentifier(identity[0])
+		var srcSeq string
+		if err := tx.QueryRowContext(ctx, `SELECT pg_get_serial_sequence($1, $2)`,
+			srcQ+"."+name, identity[1]).Scan(&srcSeq); err != nil {
+			return fmt.Errorf("failed to find identity sequence of %s.%s: %w", identity[0], identity[1], err)
+		}
+		if err := exec(fmt.Sprintf("SELECT setval(pg_get_serial_sequence($1, $2), last_value, is_called) FROM %s", srcSeq),
+			dstQ+"."+name, identity[1]); err != nil {
+			return err
+		}
+	}
+
+	// 7. 외래 키
+	foreignKeys, err := queryStrings(ctx, tx, `
+		SELECT c.relname, con.conname, pg_get_constraintdef(con.oid)
+		FROM pg_constraint con
+		JOIN pg_class c ON c.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND con.contype = 'f'
+		ORDER BY con.oid`, src)
+	if err != nil {
+		return err
+	}
+	for _, fk := range foreignKeys {
+		if err := exec(fmt.Sprintf("ALTER TABLE %s.%s ADD CONSTRAINT %s %s",
+			dstQ, quoteIdentifier(fk[0]), quoteIdentifier(fk[1]), rewrite(fk[2]))); err != nil {
+			return err
+		}
+	}
+
+	// 8. 뷰와 구체화된 뷰 (생성 순서대로)
+	views, err := queryStrings(ctx, tx, `
+		SELECT c.relname, c.relkind::text, pg_get_viewdef(c.oid)
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relkind IN ('v', 'm')
+		ORDER BY c.oid`, src)
+	if err != nil {
+		return err
+	}
+	for _, view := range views {
+		kind := "VIEW"
+		if view[1] == "m" {
+			kind = "MATERIALIZED VIEW"
+		}
+		if err := exec(fmt.Sprintf("CREATE %s %s.%s AS %s",
+			kind, dstQ, quoteIdentifier(view[0]), strings.TrimSuffix(strings.TrimSpace(rewrite(view[2])), ";"))); err != nil {
+			return err
+		}
+	}
+
+	// 9. 트리거 (데이터 복사 후 생성하여 복사 중에는 실행되지 않음)
+	triggers, err := queryStrings(ctx, tx, `
+		SELECT pg_get_triggerdef(t.oid)
+		FROM pg_trigger t
+		JOIN pg_class c ON c.oid = t.tgrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND NOT t.tgisinternal
+		ORDER BY t.oid`, src)
+	if err != nil {
+		return err
+	}
+	for _, trigger := range triggers {
+		if err := exec(rewrite(trigger[0])); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit schema clone: %w", err)
+	}
+	return nil
+}
+
+// sqlQueryer 트랜잭션과 연결 모두에서 카탈로그를 조회하기 위한 인터페이스입니다.
+type sqlQueryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// queryStrings 쿼리 결과의 모든 컬럼을 문자열로 읽습니다.
+func queryStrings(ctx context.Context, tx sqlQueryer, query string, args ...interface{}) ([][]string, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalog: %w", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	var result [][]string
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("failed to read catalog: %w", err)
+		}
+		row := make([]string, len(columns))
+		for i, v := range values {
+			row[i] = v.String
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
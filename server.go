@@ -0,0 +1,213 @@
+package pgtestkit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// database/sql의 기본 최대 유휴 연결 수 (유휴 연결을 버린 뒤 되돌릴 값)
+	defaultMaxIdleConns = 2
+)
+
+// Server 임베디드 PostgreSQL 서버의 수명 주기를 제어합니다.
+// 프로세스에는 서버가 하나만 실행되므로 Server는 StartServer, StopPostgres와 같은 서버를 다루며,
+// 같은 프로세스 안에서 여러 번 시작하고 중지할 수 있습니다.
+//
+// Restart는 데이터 디렉토리와 포트를 유지한 채 서버 프로세스만 다시 시작하므로,
+// 디스크에 있는 데이터베이스는 그대로 남고 기존 연결만 끊어집니다. 재연결 로직을 테스트할 때 사용합니다.
+// Stop과 Restart는 프로세스 전체의 서버에 영향을 주므로, 서버를 소유하는 TestMain이 있는 별도 패키지에서
+// 병렬 테스트와 데이터베이스 풀 없이 사용하세요.
+//
+// 사용 예시:
+//
+//	srv, err := pgtestkit.NewServer(pgtestkit.WithVersion(embeddedpostgres.V16))
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	if err := srv.Start(); err != nil {
+//		t.Fatal(err)
+//	}
+//	defer srv.Stop()
+//
+//	// ... 연결을 연 뒤 ...
+//	if err := srv.Restart(); err != nil {
+//		t.Fatal(err)
+//	}
+type Server struct {
+	cfg serverConfig
+}
+
+// NewServer 지정한 옵션을 검증하여 Server를 만듭니다. 서버는 Start를 호출해야 시작됩니다.
+func NewServer(opts ...ServerOption) (*Server, error) {
+	cfg, err := newServerConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Server{cfg: cfg}, nil
+}
+
+// Start 서버를 시작합니다. 서버가 이미 실행 중이면 아무것도 하지 않습니다.
+// Stop으로 중지한 서버도 다시 시작할 수 있으며, 이때 WithPort와 WithDataDir를 지정하지 않았다면
+// 새 포트와 새 데이터 디렉토리를 사용합니다.
+func (s *Server) Start() error {
+	return s.StartContext(context.Background())
+}
+
+// StartContext 컨텍스트를 지원하는 Start입니다.
+func (s *Server) StartContext(ctx context.Context) error {
+	return startServer(ctx, s.cfg)
+}
+
+// Stop 서버를 중지합니다. StopPostgres와 같습니다.
+func (s *Server) Stop() error {
+	return s.StopContext(context.Background())
+}
+
+// StopContext 컨텍스트를 지원하는 Stop입니다.
+func (s *Server) StopContext(ctx context.Context) error {
+	return StopPostgresContext(ctx)
+}
+
+// Restart 데이터 디렉토리와 포트를 유지한 채 서버 프로세스를 중지하고 다시 시작합니다.
+// 열려 있던 연결은 모두 끊어지며, 테스트 데이터베이스와 템플릿은 그대로 남습니다.
+//
+// pgtestkit이 시작한 임베디드 서버만 재시작할 수 있으며, 외부 서버(WithDatabaseURL), 공유 서버(WithSharedServer),
+// embeddedpostgres.Config로 시작한 서버는 ErrRestartUnsupported를 반환합니다.
+// 서버가 실행 중이 아니면 ErrServerNotRunning을 반환합니다.
+func (s *Server) Restart() error {
+	return s.RestartContext(context.Background())
+}
+
+// RestartContext 컨텍스트를 지원하는 Restart입니다.
+func (s *Server) RestartContext(ctx context.Context) error {
+	serverMutex.Lock()
+	defer serverMutex.Unlock()
+	return restartServer(ctx)
+}
+
+// Running 서버가 실행 중인지 반환합니다.
+func (s *Server) Running() bool {
+	serverMutex.Lock()
+	defer serverMutex.Unlock()
+	return serverStarted && !serverStopped
+}
+
+// Port 실행 중인 서버의 포트를 반환합니다. 서버가 실행 중이 아니면 0입니다.
+func (s *Server) Port() uint32 {
+	serverMutex.Lock()
+	defer serverMutex.Unlock()
+	if !serverStarted || serverStopped {
+		return 0
+	}
+	return port
+}
+
+// restartable 서버 프로세스를 이 프로세스가 재시작할 수 있는 설정인지 확인합니다.
+func restartable(cfg serverConfig) error {
+	switch {
+	case cfg.external != nil:
+		return fmt.Errorf("%w: external servers are not managed by pgtestkit", ErrRestartUnsupported)
+	case cfg.shared:
+		return fmt.Errorf("%w: shared servers are used by other processes", ErrRestartUnsupported)
+	case cfg.legacy != nil:
+		return fmt.Errorf("%w: servers started with embeddedpostgres.Config do not expose their parameters", ErrRestartUnsupported)
+	}
+	return nil
+}
+
+// restartServer 실행 중인 서버 프로세스를 같은 데이터 디렉토리와 포트로 다시 시작합니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func restartServer(ctx context.Context) error {
+	if !serverStarted || serverStopped {
+		err := serverNotRunningError()
+		logError("Cannot restart server", err)
+		return err
+	}
+	if err := restartable(activeConfig); err != nil {
+		logError("Cannot restart server", err)
+		return err
+	}
+
+	logger := getLogger().With(zap.Uint32("port", port))
+	logger.Info("Restarting PostgreSQL server")
+
+	// fast 모드는 열린 연결을 끊고 체크포인트를 남긴 뒤 종료하므로 데이터는 그대로 유지됨
+	if err := pgCtlStop(ctx, binariesDirectory, dataDirectory, "fast"); err != nil {
+		err = fmt.Errorf("failed to stop postgres for restart: %w", err)
+		logger.Error("Failed to stop PostgreSQL server", zap.Error(err))
+		return err
+	}
+
+	startCtx, cancel := context.WithTimeout(ctx, activeConfig.startTimeout)
+	defer cancel()
+	if err := pgCtlStart(startCtx, binariesDirectory, dataDirectory, port, activeConfig.startParams()); err != nil {
+		err := &StartError{Phase: StartPhaseProcess, Port: port, Err: err}
+		logger.Error("Failed to start PostgreSQL server again", zap.Error(err))
+		return err
+	}
+
+	// 이전 서버 프로세스에 연결되어 있던 유휴 연결을 버려 다음 쿼리가 새 연결을 사용하도록 함
+	discardIdleConns(baseDBClient)
+	for _, shared := range sharedDatabases {
+		discardIdleConns(shared.admin)
+	}
+
+	if err := waitForPostgresToBeReady(ctx, baseDBClient, logger); err != nil {
+		err := &StartError{Phase: StartPhaseReady, Port: port, Err: err}
+		logger.Error("PostgreSQL server is not ready after restart", zap.Error(err))
+		return err
+	}
+
+	logger.Info("PostgreSQL server restarted")
+	return nil
+}
+
+// discardIdleConns db의 유휴 연결을 모두 닫습니다. 재시작하는 동안 사용 중이던 연결은 다음 쿼리에서 오류가 날 수 있습니다.
+func discardIdleConns(db *sql.DB) {
+	db.SetMaxIdleConns(-1)
+	db.SetMaxIdleConns(defaultMaxIdleConns)
+}
+
+// pgCtlStart pg_ctl로 데이터 디렉토리의 서버를 시작하고 연결을 받을 때까지 기다립니다.
+// 서버 프로세스의 출력은 -l로 데이터 디렉토리의 로그 파일에 남기므로, logging_collector가 시작되기 전의
+// 시작 오류도 확인할 수 있습니다. 오류 메시지에는 pg_ctl의 출력만 담습니다.
+func pgCtlStart(ctx context.Context, binariesDir, dataDir string, port uint32, params map[string]string) error {
+	logDir := filepath.Join(dataDir, serverLogDir)
+	if err := os.MkdirAll(logDir, 0o700); err != nil {
+		return fmt.Errorf("failed to create server log directory: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, filepath.Join(binariesDir, "bin", "pg_ctl"),
+		"start", "-w", "-D", dataDir, "-l", filepath.Join(logDir, postmasterLogFile),
+		"-o", pgCtlOptions(port, params))
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("pg_ctl start failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// pgCtlOptions pg_ctl -o로 서버 프로세스에 전달할 포트와 파라미터를 만듭니다.
+// embeddedpostgres와 같은 형식이며, 값에 공백이 있을 수 있으므로 큰따옴표로 감쌉니다.
+func pgCtlOptions(port uint32, params map[string]string) string {
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	options := []string{fmt.Sprintf("-p %d", port)}
+	for _, name := range names {
+		options = append(options, fmt.Sprintf("-c %s=\"%s\"", name, params[name]))
+	}
+	return strings.Join(options, " ")
+}
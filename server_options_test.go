@@ -0,0 +1,90 @@
+package pgtestkit
+
+import (
+	"strings"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+)
+
+func TestNewServerConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []ServerOption
+		wantErr string
+	}{
+		{name: "defaults"},
+		{
+			name: "custom settings",
+			opts: []ServerOption{
+				WithVersion(embeddedpostgres.V16),
+				WithUser("app"),
+				WithPassword("secret"),
+				WithPort(54321),
+				WithPostgresParam("max_connections", "200"),
+			},
+		},
+		{name: "empty user", opts: []ServerOption{WithUser("")}, wantErr: "user cannot be empty"},
+		{name: "empty password", opts: []ServerOption{WithPassword("")}, wantErr: "password cannot be empty"},
+		{name: "port out of range", opts: []ServerOption{WithPort(70000)}, wantErr: "out of range"},
+		{name: "non-positive timeout", opts: []ServerOption{WithStartTimeout(0)}, wantErr: "start timeout"},
+		{name: "port param", opts: []ServerOption{WithPostgresParam("port", "5432")}, wantErr: "WithPort"},
+		{name: "invalid param name", opts: []ServerOption{WithPostgresParam("a b", "1")}, wantErr: "invalid postgres parameter"},
+		{name: "unnamed migrated template", opts: []ServerOption{WithMigratedTemplate("", SQLMigrator(nil))}, wantErr: "template name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newServerConfig(tt.opts)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWithLegacyConfigCredentials(t *testing.T) {
+	passwords := []string{
+		"secret",
+		"p@ss:word",
+		"with/slash",
+		"hash#and?query",
+		"bad%zzescape",
+		"valid%41escape", // url.Parse라면 "validAescape"로 바뀜
+		"@localhost:5432",
+	}
+	for _, password := range passwords {
+		legacy := embeddedpostgres.DefaultConfig().Username("app").Password(password)
+		opts, err := withLegacyConfig(&legacy)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", password, err)
+		}
+		cfg, err := newServerConfig(opts)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", password, err)
+		}
+		if cfg.user != "app" {
+			t.Errorf("%q: expected user 'app', got %q", password, cfg.user)
+		}
+		if cfg.password != password {
+			t.Errorf("Expected password %q, got %q", password, cfg.password)
+		}
+		if cfg.legacy == nil {
+			t.Error("Expected legacy config to be kept")
+		}
+	}
+
+	legacy := embeddedpostgres.DefaultConfig().Username("")
+	if _, err := withLegacyConfig(&legacy); err == nil {
+		t.Error("Expected an error when the credentials cannot be recovered")
+	}
+	if opts, err := withLegacyConfig(nil); err != nil || opts != nil {
+		t.Errorf("Expected no options for a nil config, got %v (%v)", opts, err)
+	}
+}
@@ -0,0 +1,73 @@
+package pgtestkit
+
+import (
+	"errors"
+	"testing"
+
+	embeddedpostgres "github.com/fergusstrange/embedded-postgres"
+)
+
+func TestPgCtlOptions(t *testing.T) {
+	got := pgCtlOptions(5433, map[string]string{
+		"log_line_prefix": "%m [%p] ",
+		"fsync":           "off",
+	})
+	want := `-p 5433 -c fsync="off" -c log_line_prefix="%m [%p] "`
+	if got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+
+	if got := pgCtlOptions(5433, nil); got != "-p 5433" {
+		t.Errorf("Expected only the port, got %s", got)
+	}
+}
+
+func TestRestartable(t *testing.T) {
+	t.Setenv(DatabaseURLEnv, "")
+	t.Setenv(SharedServerEnv, "")
+	legacy := embeddedpostgres.DefaultConfig()
+	legacyOpts, err := withLegacyConfig(&legacy)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		opts []ServerOption
+		ok   bool
+	}{
+		{name: "embedded", ok: true},
+		{name: "data dir", opts: []ServerOption{WithDataDir(t.TempDir())}, ok: true},
+		{name: "external", opts: []ServerOption{WithDatabaseURL("postgres://ci@postgres:5432/postgres")}},
+		{name: "shared", opts: []ServerOption{WithSharedServer(true)}},
+		{name: "legacy", opts: legacyOpts},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := newServerConfig(tt.opts)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			err = restartable(cfg)
+			if tt.ok && err != nil {
+				t.Errorf("Expected the server to be restartable, got %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrRestartUnsupported) {
+				t.Errorf("Expected ErrRestartUnsupported, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewServerValidatesOptions(t *testing.T) {
+	if _, err := NewServer(WithPort(70000)); err == nil {
+		t.Error("Expected an out-of-range port to be rejected")
+	}
+	srv, err := NewServer()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if srv.Running() {
+		t.Error("Expected a new server not to be running")
+	}
+}
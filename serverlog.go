@@ -0,0 +1,242 @@
+package pgtestkit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// 서버 로그 파일 (데이터 디렉토리 기준)
+	serverLogDir  = "log"
+	serverLogFile = "pgtestkit.log"
+
+	// Restart로 다시 시작한 서버 프로세스의 표준 출력과 표준 오류 (logging_collector 시작 전의 출력 포함)
+	postmasterLogFile = "postmaster.log"
+
+	// 로그 줄을 데이터베이스별로 나누기 위한 접두사 (%d: 데이터베이스 이름)
+	serverLogLinePrefix = "%m [%p] {%d} "
+
+	// 서버 로그 파일을 확인하는 주기
+	serverLogPollInterval = 100 * time.Millisecond
+)
+
+var (
+	// serverLogLinePrefix로 시작하는 줄에서 데이터베이스 이름을 찾는 정규식
+	serverLogLinePattern = regexp.MustCompile(`^[^\[]*\[\d+\] \{([^}]*)\} `)
+
+	// 서버 로그를 읽어 데이터베이스별 구독자에게 전달하는 tail
+	serverLogs = &serverLogTail{subs: make(map[string]map[int]func(string))}
+
+	// log_statement와 log_min_error_statement에 허용되는 값
+	logStatementLevels = map[string]bool{"none": true, "ddl": true, "mod": true, "all": true}
+	logErrorLevels     = map[string]bool{
+		"debug5": true, "debug4": true, "debug3": true, "debug2": true, "debug1": true,
+		"info": true, "notice": true, "warning": true, "error": true, "log": true, "fatal": true, "panic": true,
+	}
+)
+
+// WithLogStatement 서버가 로그로 남길 SQL 문장의 종류(log_statement)를 지정합니다.
+// "none"(기본값), "ddl", "mod", "all" 중 하나이며, 로그는 WithServerLogs로 테스트 출력에 전달됩니다.
+func WithLogStatement(level string) ServerOption {
+	return WithPostgresParam("log_statement", level)
+}
+
+// WithLogMinErrorStatement 오류를 일으킨 SQL 문장을 함께 남길 최소 심각도(log_min_error_statement)를 지정합니다.
+// PostgreSQL의 기본값은 "error"입니다.
+func WithLogMinErrorStatement(level string) ServerOption {
+	return WithPostgresParam("log_min_error_statement", level)
+}
+
+// WithServerLogs 테스트 데이터베이스에서 발생한 서버 로그(오류, 경고, log_statement로 남긴 SQL)를
+// 소유한 테스트의 t.Log로 전달할지 지정합니다. 기본값은 true이며, WithTB(New와 NewOf)가 필요합니다.
+//
+// 서버 로그는 pgtestkit이 ServerOption으로 시작한 임베디드 서버에서만 읽을 수 있으며
+// (외부 서버와 embeddedpostgres.Config로 시작한 서버는 제외), 테스트마다 데이터베이스를 만드는 경우에만
+// 전달됩니다. 트랜잭션 격리와 스키마 격리 모드는 여러 테스트가 데이터베이스를 공유하므로 전달하지 않습니다.
+func WithServerLogs(enabled bool) Option {
+	return func(o *dbOptions) {
+		o.serverLogs = &enabled
+	}
+}
+
+// serverLogParams 서버 로그를 파일로 모으고 데이터베이스 이름을 접두사로 남기는 설정입니다.
+// WithPostgresParam으로 지정한 값이 우선합니다.
+func serverLogParams() map[string]string {
+	return map[string]string{
+		"logging_collector": "on",
+		"log_directory":     serverLogDir,
+		"log_filename":      serverLogFile,
+		"log_rotation_age":  "0",
+		"log_rotation_size": "0",
+		"log_line_prefix":   serverLogLinePrefix,
+	}
+}
+
+// validateLogParams log_statement와 log_min_error_statement 값을 확인합니다.
+func validateLogParams(params map[string]string) error {
+	if level, ok := params["log_statement"]; ok && !logStatementLevels[level] {
+		return fmt.Errorf("invalid log_statement %q", level)
+	}
+	if level, ok := params["log_min_error_statement"]; ok && !logErrorLevels[level] {
+		return fmt.Errorf("invalid log_min_error_statement %q", level)
+	}
+	return nil
+}
+
+// serverLogPath 실행 중인 서버의 로그 파일 경로를 반환합니다. 로그를 읽을 수 없는 서버이면 빈 문자열입니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func serverLogPath() string {
+	if activeConfig.external != nil || activeConfig.legacy != nil || dataDirectory == "" {
+		return ""
+	}
+	return filepath.Join(dataDirectory, serverLogDir, serverLogFile)
+}
+
+// routeServerLogs dbName의 서버 로그를 tb로 전달하고, 전달을 멈추는 함수를 반환합니다.
+// 테스트가 끝난 뒤에는 t.Log를 호출할 수 없으므로 tb.Cleanup에서도 전달을 멈춥니다.
+// path는 serverMutex를 보유한 상태에서 serverLogPath로 얻은 로그 파일 경로입니다.
+func (c *DBClient) routeServerLogs(options dbOptions, path string) {
+	if options.tb == nil || !*options.serverLogs {
+		return
+	}
+	if path == "" {
+		return
+	}
+
+	tb := options.tb
+	stop := serverLogs.subscribe(path, c.DBName, func(line string) {
+		tb.Log("postgres: " + line)
+	})
+	var once sync.Once
+	c.stopLogs = func() { once.Do(stop) }
+	tb.Cleanup(c.stopLogs)
+}
+
+// serverLogTail 서버 로그 파일을 주기적으로 읽어 데이터베이스별 구독자에게 줄 단위로 전달합니다.
+// 구독자가 있는 동안에만 파일을 읽습니다.
+type serverLogTail struct {
+	mu      sync.Mutex
+	path    string
+	offset  int64
+	partial []byte // 아직 끝나지 않은 마지막 줄
+	current string // 마지막으로 읽은 접두사의 데이터베이스 (접두사 없는 이어지는 줄에 사용)
+	subs    map[string]map[int]func(string)
+	nextID  int
+	running bool
+}
+
+// subscribe dbName의 로그 줄을 fn으로 전달하고, 구독을 해제하는 함수를 반환합니다.
+// 구독을 해제할 때는 그때까지 기록된 줄을 모두 전달합니다.
+func (s *serverLogTail) subscribe(path, dbName string, fn func(string)) func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.running || s.path != path {
+		// 이전 내용은 구독자가 없으므로 파일 끝부터 읽음
+		s.path = path
+		s.offset = 0
+		if info, err := os.Stat(path); err == nil {
+			s.offset = info.Size()
+		}
+		s.partial = nil
+		s.current = ""
+	}
+	if !s.running {
+		s.running = true
+		go s.poll()
+	}
+
+	s.nextID++
+	id := s.nextID
+	if s.subs[dbName] == nil {
+		s.subs[dbName] = make(map[int]func(string))
+	}
+	s.subs[dbName][id] = fn
+
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.read()
+		delete(s.subs[dbName], id)
+		if len(s.subs[dbName]) == 0 {
+			delete(s.subs, dbName)
+		}
+	}
+}
+
+// poll 구독자가 남아 있는 동안 로그 파일을 주기적으로 읽습니다.
+func (s *serverLogTail) poll() {
+	ticker := time.NewTicker(serverLogPollInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		s.mu.Lock()
+		if len(s.subs) == 0 {
+			s.running = false
+			s.mu.Unlock()
+			return
+		}
+		s.read()
+		s.mu.Unlock()
+	}
+}
+
+// read 마지막으로 읽은 위치 이후의 로그를 읽어 전달합니다. 호출자는 s.mu를 보유하고 있어야 합니다.
+func (s *serverLogTail) read() {
+	f, err := os.Open(s.path)
+	if err != nil {
+		// 서버가 아직 로그 파일을 만들지 않았을 수 있음
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	if info, err := f.Stat(); err == nil && info.Size() < s.offset {
+		// 파일이 다시 만들어졌으면 처음부터 읽음
+		s.offset = 0
+		s.partial = nil
+	}
+	if _, err := f.Seek(s.offset, io.SeekStart); err != nil {
+		getLogger().Debug("Failed to seek server log", zap.String("path", s.path), zap.Error(err))
+		return
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		getLogger().Debug("Failed to read server log", zap.String("path", s.path), zap.Error(err))
+	}
+	s.offset += int64(len(data))
+	s.dispatch(data)
+}
+
+// dispatch 완성된 줄을 데이터베이스별로 나누어 전달하고, 끝나지 않은 줄은 다음에 이어서 읽도록 남깁니다.
+// 호출자는 s.mu를 보유하고 있어야 합니다.
+func (s *serverLogTail) dispatch(data []byte) {
+	data = append(s.partial, data...)
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(bytes.TrimRight(data[:i], "\r"))
+		data = data[i+1:]
+
+		// DETAIL, STATEMENT 등 접두사 없이 이어지는 줄은 앞 줄의 데이터베이스로 보냄
+		if m := serverLogLinePattern.FindStringSubmatch(line); m != nil {
+			s.current = m[1]
+		}
+		if s.current == "" {
+			continue
+		}
+		for _, fn := range s.subs[s.current] {
+			fn(line)
+		}
+	}
+	s.partial = append([]byte(nil), data...)
+}
@@ -0,0 +1,80 @@
+package pgtestkit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServerLogDispatch(t *testing.T) {
+	var got []string
+	s := &serverLogTail{subs: map[string]map[int]func(string){
+		"testdb_a": {1: func(line string) { got = append(got, line) }},
+	}}
+
+	s.dispatch([]byte("2024-01-02 03:04:05.678 UTC [42] {testdb_a} ERROR:  relation \"missing\" does not exist\n" +
+		"2024-01-02 03:04:05.678 UTC [42] {testdb_a} STATEMENT:  SELECT *\n\tFROM missing\n" +
+		"2024-01-02 03:04:05.679 UTC [43] {testdb_b} LOG:  statement: SELECT 1\n" +
+		"2024-01-02 03:04:05.680 UTC [44] {} LOG:  checkpoint starting\n" +
+		"2024-01-02 03:04:05.681 UTC [42] {testdb_a} LOG:  unfinished"))
+	want := []string{
+		`2024-01-02 03:04:05.678 UTC [42] {testdb_a} ERROR:  relation "missing" does not exist`,
+		"2024-01-02 03:04:05.678 UTC [42] {testdb_a} STATEMENT:  SELECT *",
+		"\tFROM missing",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dispatch() delivered %q, want %q", got, want)
+	}
+
+	// 끝나지 않은 줄은 다음 읽기에서 이어짐
+	got = nil
+	s.dispatch([]byte(" line\n"))
+	if len(got) != 1 || !strings.HasSuffix(got[0], "LOG:  unfinished line") {
+		t.Errorf("Expected partial line to be completed, got %q", got)
+	}
+}
+
+func TestServerLogSubscribe(t *testing.T) {
+	path := filepath.Join(t.TempDir(), serverLogFile)
+	if err := os.WriteFile(path, []byte("2024-01-02 03:04:05.678 UTC [1] {testdb_a} LOG:  before\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &serverLogTail{subs: make(map[string]map[int]func(string))}
+	var got []string
+	stop := s.subscribe(path, "testdb_a", func(line string) { got = append(got, line) })
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("2024-01-02 03:04:05.679 UTC [1] {testdb_a} LOG:  after\n"); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	// 구독 해제 시 남은 줄을 모두 전달
+	stop()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(got) != 1 || !strings.HasSuffix(got[0], "LOG:  after") {
+		t.Errorf("Expected only lines written after subscribing, got %q", got)
+	}
+	if len(s.subs) != 0 {
+		t.Errorf("Expected subscription to be removed, got %v", s.subs)
+	}
+}
+
+func TestValidateLogParams(t *testing.T) {
+	if _, err := newServerConfig([]ServerOption{WithLogStatement("all"), WithLogMinErrorStatement("warning")}); err != nil {
+		t.Errorf("Expected valid log levels, got %v", err)
+	}
+	if _, err := newServerConfig([]ServerOption{WithLogStatement("everything")}); err == nil {
+		t.Error("Expected error for invalid log_statement")
+	}
+	if _, err := newServerConfig([]ServerOption{WithLogMinErrorStatement("critical")}); err == nil {
+		t.Error("Expected error for invalid log_min_error_statement")
+	}
+}
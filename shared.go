@@ -0,0 +1,304 @@
+package pgtestkit
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// SharedServerEnv 여러 go test 프로세스가 하나의 서버를 공유할지 지정하는 환경 변수입니다.
+	// WithSharedServer와 같은 역할을 하며, strconv.ParseBool이 이해하는 값(1, true 등)을 사용합니다.
+	SharedServerEnv = "PGTESTKIT_SHARED_SERVER"
+
+	// 공유 서버가 살아 있는지 확인할 때 기다리는 최대 시간
+	sharedServerProbeTimeout = 2 * time.Second
+)
+
+// sharedState 공유 서버의 상태 파일 내용입니다.
+// 상태 파일은 잠금 파일로 보호되며, 서버에 연결된 프로세스 목록으로 참조 횟수를 관리합니다.
+type sharedState struct {
+	Port        uint32 `json:"port"`
+	User        string `json:"user"`
+	Password    string `json:"password"`
+	RuntimeDir  string `json:"runtime_dir"`
+	DataDir     string `json:"data_dir"`
+	BinariesDir string `json:"binaries_dir"`
+	Clients     []int  `json:"clients"`            // 서버를 사용 중인 프로세스의 PID
+	Preserve    bool   `json:"preserve,omitempty"` // 실패한 테스트의 데이터베이스가 보존되었는지 여부
+}
+
+// WithSharedServer 같은 설정을 사용하는 여러 go test 프로세스가 하나의 서버를 공유하도록 합니다.
+// go test ./...는 패키지마다 별도의 프로세스를 실행하므로, 이 옵션을 사용하면 처음 실행된 프로세스만 서버를 시작하고
+// 이후 프로세스는 상태 파일에 기록된 포트로 연결합니다. 서버는 마지막 프로세스가 StopPostgres를 호출할 때 중지되며,
+// 비정상 종료된 프로세스가 남긴 상태는 다음 실행에서 감지하여 정리합니다.
+//
+// 공유 서버에서는 데이터베이스 이름이 프로세스 사이에서 겹치지 않아야 하므로,
+// CreateTemplateDB와 WithMigratedTemplate으로 만드는 템플릿 이름은 패키지마다 다르게 지정하세요.
+// 다른 프로세스가 만든 템플릿과 이름이 겹치면 ErrTemplateExists를 반환합니다.
+// 이 옵션을 지정하지 않으면 PGTESTKIT_SHARED_SERVER 환경 변수의 값을 따릅니다.
+func WithSharedServer(enabled bool) ServerOption {
+	return func(c *serverConfig) {
+		c.shared = enabled
+	}
+}
+
+// sharedServerKey 공유 서버를 구분하는 키를 생성합니다.
+// 서버 설정이 같은 프로세스끼리만 서버를 공유합니다.
+func sharedServerKey(cfg serverConfig) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s\x00%d\x00%s\x00%s",
+		cfg.version, cfg.user, cfg.password, cfg.locale, cfg.port, cfg.dataDir, cfg.binariesDir)
+
+	names := make([]string, 0, len(cfg.params))
+	for name := range cfg.params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(h, "\x00%s=%s", name, cfg.params[name])
+	}
+
+	return fmt.Sprintf("%s-%s", cfg.version, hex.EncodeToString(h.Sum(nil))[:16])
+}
+
+// sharedStatePaths 공유 서버의 상태 파일과 잠금 파일 경로를 반환합니다.
+func sharedStatePaths(cfg serverConfig) (statePath, lockPath string, err error) {
+	root, err := defaultCacheRoot("shared")
+	if err != nil {
+		return "", "", err
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create shared server state directory: %w", err)
+	}
+
+	key := sharedServerKey(cfg)
+	return filepath.Join(root, key+".json"), filepath.Join(root, key+".lock"), nil
+}
+
+// readSharedState 상태 파일을 읽습니다. 파일이 없거나 손상되었으면 nil을 반환합니다.
+func readSharedState(path string, logger *zap.Logger) *sharedState {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			logger.Warn("Failed to read shared server state", zap.String("path", path), zap.Error(err))
+		}
+		return nil
+	}
+
+	var state sharedState
+	if err := json.Unmarshal(data, &state); err != nil {
+		logger.Warn("Ignoring corrupted shared server state", zap.String("path", path), zap.Error(err))
+		return nil
+	}
+	return &state
+}
+
+// writeSharedState 상태 파일을 원자적으로 기록합니다.
+// 비밀번호가 포함되므로 소유자만 읽을 수 있도록 생성합니다.
+func writeSharedState(path string, state *sharedState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode shared server state: %w", err)
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write shared server state: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write shared server state: %w", err)
+	}
+	return nil
+}
+
+// liveClients 실행 중인 프로세스만 남기고, exclude와 중복된 PID를 제거합니다.
+func liveClients(pids []int, exclude int) []int {
+	seen := make(map[int]bool, len(pids))
+	live := make([]int, 0, len(pids))
+	for _, pid := range pids {
+		if pid == exclude || seen[pid] || !processAlive(pid) {
+			continue
+		}
+		seen[pid] = true
+		live = append(live, pid)
+	}
+	return live
+}
+
+// attachSharedServer 상태 파일에 기록된 공유 서버에 연결합니다.
+// 사용 가능한 서버가 없으면 새로 시작하고 상태 파일에 기록합니다. 호출자는 serverMutex를 보유하고 있어야 합니다.
+func attachSharedServer(ctx context.Context, cfg serverConfig, logger *zap.Logger) (*sql.DB, error) {
+	statePath, lockPath, err := sharedStatePaths(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 서버 시작이 끝날 때까지 다른 프로세스가 기다리도록 잠금 유지
+	unlock, err := lockFile(ctx, lockPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock shared server state: %w", err)
+	}
+	defer unlock()
+
+	pid := os.Getpid()
+	if state := readSharedState(statePath, logger); state != nil {
+		useSharedState(state)
+		db, err := probeSharedServer(ctx)
+		if err == nil {
+			state.Clients = append(liveClients(state.Clients, pid), pid)
+			if err := writeSharedState(statePath, state); err != nil {
+				db.Close()
+				return nil, err
+			}
+			logger.Info("Attached to shared PostgreSQL server",
+				zap.Uint32("port", port),
+				zap.Int("clients", len(state.Clients)))
+			return db, nil
+		}
+
+		// 서버를 시작한 프로세스가 비정상 종료되어 서버가 남아 있지 않은 경우
+		logger.Warn("Discarding stale shared PostgreSQL server state",
+			zap.String("path", statePath),
+			zap.Uint32("port", state.Port),
+			zap.Error(err))
+		cleanupStaleSharedServer(ctx, state, logger)
+		if err := os.Remove(statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove stale shared server state: %w", err)
+		}
+	}
+
+	db, err := launchServer(ctx, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &sharedState{
+		Port:        port,
+		User:        cfg.user,
+		Password:    cfg.password,
+		RuntimeDir:  runtimeDirectory,
+		DataDir:     dataDirectory,
+		BinariesDir: binariesDirectory,
+		Clients:     []int{pid},
+	}
+	if err := writeSharedState(statePath, state); err != nil {
+		db.Close()
+		if stopErr := server.Stop(); stopErr != nil {
+			logError("Failed to stop PostgreSQL server after state write error", stopErr)
+		}
+		return nil, err
+	}
+
+	logger.Info("Published shared PostgreSQL server", zap.String("state", statePath))
+	return db, nil
+}
+
+// useSharedState 공유 서버의 포트, 계정, 경로를 이 프로세스의 설정으로 사용합니다.
+func useSharedState(state *sharedState) {
+	port = state.Port
+	activeConfig.user = state.User
+	activeConfig.password = state.Password
+	runtimeDirectory = state.RuntimeDir
+	dataDirectory = state.DataDir
+	binariesDirectory = state.BinariesDir
+}
+
+// probeSharedServer 공유 서버가 연결을 받을 수 있는지 확인하고, 기본 데이터베이스 연결을 반환합니다.
+func probeSharedServer(ctx context.Context) (*sql.DB, error) {
+	db, err := sql.Open("pgx", getConnectionString(DefaultDB))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database connection: %w", err)
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, sharedServerProbeTimeout)
+	defer cancel()
+	if err := db.PingContext(pingCtx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("shared server is not reachable: %w", err)
+	}
+	return db, nil
+}
+
+// cleanupStaleSharedServer 응답하지 않는 공유 서버의 프로세스와 런타임 디렉토리를 정리합니다.
+func cleanupStaleSharedServer(ctx context.Context, state *sharedState, logger *zap.Logger) {
+	if fileExists(filepath.Join(state.DataDir, "postmaster.pid")) {
+		if err := pgCtlStop(ctx, state.BinariesDir, state.DataDir, "immediate"); err != nil {
+			logger.Debug("Failed to stop stale shared PostgreSQL server", zap.Error(err))
+		}
+	}
+	if state.RuntimeDir != "" && !state.Preserve {
+		if err := os.RemoveAll(state.RuntimeDir); err != nil {
+			logger.Warn("Failed to remove stale runtime directory",
+				zap.String("path", state.RuntimeDir),
+				zap.Error(err))
+		}
+	}
+}
+
+// detachSharedServer 공유 서버의 사용자 목록에서 이 프로세스를 제거합니다.
+// 마지막 사용자였다면 last가 true이며, 호출자는 서버를 중지한 뒤 unlock을 호출해야 합니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func detachSharedServer(ctx context.Context, logger *zap.Logger) (last bool, unlock func(), err error) {
+	statePath, lockPath, err := sharedStatePaths(activeConfig)
+	if err != nil {
+		return false, nil, err
+	}
+
+	unlock, err = lockFile(ctx, lockPath)
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to lock shared server state: %w", err)
+	}
+
+	state := readSharedState(statePath, logger)
+	if state == nil || state.Port != port {
+		// 다른 프로세스가 이 서버를 응답하지 않는 것으로 판단하고 교체한 경우
+		logger.Warn("Shared server state no longer refers to this server, stopping it",
+			zap.Uint32("port", port))
+		return true, unlock, nil
+	}
+
+	state.Clients = liveClients(state.Clients, os.Getpid())
+	state.Preserve = state.Preserve || preserveDataDirectory
+	if len(state.Clients) > 0 {
+		if err := writeSharedState(statePath, state); err != nil {
+			unlock()
+			return false, nil, err
+		}
+		logger.Info("Detached from shared PostgreSQL server, other processes are still using it",
+			zap.Int("clients", len(state.Clients)))
+		return false, unlock, nil
+	}
+
+	preserveDataDirectory = state.Preserve
+	if err := os.Remove(statePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Warn("Failed to remove shared server state", zap.String("path", statePath), zap.Error(err))
+	}
+	logger.Info("Last process detached from shared PostgreSQL server")
+	return true, unlock, nil
+}
+
+// pgCtlStop pg_ctl로 데이터 디렉토리의 서버를 중지합니다.
+// 다른 프로세스가 시작하여 EmbeddedPostgres 객체가 없는 서버를 중지할 때 사용합니다.
+func pgCtlStop(ctx context.Context, binariesDir, dataDir, mode string) error {
+	cmd := exec.CommandContext(ctx, filepath.Join(binariesDir, "bin", "pg_ctl"),
+		"stop", "-w", "-D", dataDir, "-m", mode)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("pg_ctl stop failed: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package pgtestkit
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestSharedServerKey(t *testing.T) {
+	newConfig := func(opts ...ServerOption) serverConfig {
+		cfg, err := newServerConfig(opts)
+		if err != nil {
+			t.Fatalf("Failed to create server config: %v", err)
+		}
+		return cfg
+	}
+
+	base := sharedServerKey(newConfig(WithPostgresParam("a", "1"), WithPostgresParam("b", "2")))
+	if got := sharedServerKey(newConfig(WithPostgresParam("b", "2"), WithPostgresParam("a", "1"))); got != base {
+		t.Errorf("Expected option order not to change the key, got %s and %s", base, got)
+	}
+	if got := sharedServerKey(newConfig(WithPostgresParam("a", "1"), WithPostgresParam("b", "3"))); got == base {
+		t.Error("Expected different parameters to produce a different key")
+	}
+	if got := sharedServerKey(newConfig(WithPostgresParam("a", "1"), WithPostgresParam("b", "2"), WithPassword("other"))); got == base {
+		t.Error("Expected different credentials to produce a different key")
+	}
+}
+
+func TestSharedStateClients(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	self := os.Getpid()
+	const deadPID = 1 << 30
+
+	state := &sharedState{Port: 54321, User: "postgres", Password: "postgres", Clients: []int{self, deadPID, self}}
+	if err := writeSharedState(path, state); err != nil {
+		t.Fatalf("Failed to write state: %v", err)
+	}
+
+	got := readSharedState(path, zap.NewNop())
+	if got == nil {
+		t.Fatal("Expected state to be read back")
+	}
+	if got.Port != state.Port {
+		t.Errorf("Expected port %d, got %d", state.Port, got.Port)
+	}
+	if clients := liveClients(got.Clients, 0); !reflect.DeepEqual(clients, []int{self}) {
+		t.Errorf("Expected only the live client to remain, got %v", clients)
+	}
+	if clients := liveClients(got.Clients, self); len(clients) != 0 {
+		t.Errorf("Expected excluded client to be removed, got %v", clients)
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o600); err != nil {
+		t.Fatalf("Failed to corrupt state: %v", err)
+	}
+	if readSharedState(path, zap.NewNop()) != nil {
+		t.Error("Expected corrupted state to be ignored")
+	}
+}
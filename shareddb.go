@@ -0,0 +1,69 @@
+package pgtestkit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// 격리 모드에서 여러 테스트가 공유하는 데이터베이스 (격리 방식과 템플릿별, serverMutex로 보호)
+	sharedDatabases = map[string]*sharedDatabase{}
+)
+
+// sharedDatabase 트랜잭션 격리나 스키마 격리 모드에서 여러 테스트가 공유하는 데이터베이스입니다.
+type sharedDatabase struct {
+	name  string
+	admin *sql.DB // 스키마 생성과 삭제 등 관리 작업에 사용하는 연결
+	kept  bool    // 실패한 테스트의 스키마가 보존되어 삭제하면 안 되는지 여부
+}
+
+// getSharedDatabase 격리 방식과 템플릿별로 공유되는 데이터베이스를 반환하고, 없으면 템플릿을 복제하여 생성합니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func getSharedDatabase(ctx context.Context, kind, template string, logger *zap.Logger) (*sharedDatabase, error) {
+	key := kind + "\x00" + template
+	if shared, ok := sharedDatabases[key]; ok {
+		return shared, nil
+	}
+
+	dbName := generateTestDBName(kind)
+	logger.Info("Creating shared database",
+		zap.String("isolation", kind),
+		zap.String("database", dbName),
+		zap.String("template", template))
+	if err := createDatabase(ctx, dbName, template); err != nil {
+		return nil, fmt.Errorf("failed to create shared %s database: %w", kind, err)
+	}
+
+	admin, err := sql.Open("pgx", getConnectionString(dbName))
+	if err != nil {
+		if dropErr := dropDatabase(context.WithoutCancel(ctx), dbName); dropErr != nil {
+			logError("Failed to clean up shared database after connection error", dropErr)
+		}
+		return nil, fmt.Errorf("failed to open shared %s database: %w", kind, err)
+	}
+
+	shared := &sharedDatabase{name: dbName, admin: admin}
+	sharedDatabases[key] = shared
+	return shared, nil
+}
+
+// dropSharedDatabases 공유 데이터베이스의 연결을 닫고 모두 삭제합니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func dropSharedDatabases(ctx context.Context) []error {
+	var errs []error
+	for key, shared := range sharedDatabases {
+		if err := shared.admin.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close shared database %s: %w", shared.name, err))
+		}
+		if shared.kept {
+			getLogger().Info("Keeping shared database with schemas of failed tests", zap.String("database", shared.name))
+		} else if err := dropDatabase(ctx, shared.name); err != nil {
+			errs = append(errs, fmt.Errorf("failed to drop shared database %s: %w", shared.name, err))
+		}
+		delete(sharedDatabases, key)
+	}
+	return errs
+}
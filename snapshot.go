@@ -0,0 +1,289 @@
+package pgtestkit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// 시스템 스키마를 제외한 사용자 스키마 조건 (n은 pg_namespace)
+const userSchemaFilter = `n.nspname !~ '^pg_' AND n.nspname <> 'information_schema'`
+
+// 확장 기능이 소유한 객체를 제외하는 조건 (%s는 카탈로그, %s는 객체 oid)
+const notExtensionMember = `NOT EXISTS (SELECT 1 FROM pg_depend d
+	WHERE d.classid = '%s'::regclass AND d.objid = %s AND d.deptype = 'e')`
+
+// snapshotObject 스냅샷에 기록되는 하나의 객체입니다.
+type snapshotObject struct {
+	kind   string   // 정렬과 출력에 사용하는 종류 (table, view 등)
+	name   string   // 스키마로 한정된 이름
+	header string   // 첫 줄 (종류와 이름 뒤에 붙는 속성)
+	lines  []string // 들여쓰기하여 출력할 세부 항목
+}
+
+// SchemaSnapshot db의 사용자 스키마를 pg_catalog에서 읽어 정규화된 텍스트로 반환합니다.
+// 확장 기능, 스키마, enum, 시퀀스, 테이블(컬럼, 타입, 기본값, 제약 조건, 인덱스, 트리거), 뷰,
+// 구체화된 뷰, 함수를 포함합니다. 객체는 종류와 이름 순으로 정렬되고 모든 이름은 스키마로 한정되므로,
+// 같은 스키마는 생성 순서나 연결의 search_path와 관계없이 항상 같은 텍스트가 됩니다.
+// OID, 시퀀스의 현재 값, 테이블 데이터처럼 스키마가 아닌 정보는 포함하지 않습니다.
+//
+// 골든 파일과 비교하려면 AssertSchemaGolden을 사용하세요.
+func SchemaSnapshot(db *sql.DB) (string, error) {
+	return SchemaSnapshotContext(context.Background(), db)
+}
+
+// SchemaSnapshotContext 컨텍스트를 지원하는 SchemaSnapshot입니다.
+func SchemaSnapshotContext(ctx context.Context, db *sql.DB) (string, error) {
+	if db == nil {
+		return "", fmt.Errorf("database must not be nil")
+	}
+	return captureSchema(ctx, db)
+}
+
+// captureSchema 사용자 스키마의 카탈로그를 읽어 결정적인 텍스트로 만듭니다.
+// 객체는 종류와 이름 순으로 정렬되며, OID나 시퀀스 값처럼 데이터나 생성 순서에 따라 달라지는 값은 포함하지 않습니다.
+func captureSchema(ctx context.Context, db *sql.DB) (string, error) {
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return "", fmt.Errorf("failed to begin schema snapshot: %w", err)
+	}
+	defer tx.Rollback()
+
+	// search_path를 비우면 pg_get_*def 함수가 모든 이름을 스키마로 한정하여 출력하므로 연결 설정과 무관해짐
+	if _, err := tx.ExecContext(ctx, `SELECT pg_catalog.set_config('search_path', '', true)`); err != nil {
+		return "", fmt.Errorf("failed to reset search_path for schema snapshot: %w", err)
+	}
+
+	objects := map[string]*snapshotObject{}
+	object := func(kind, name string) *snapshotObject {
+		key := kind + " " + name
+		if objects[key] == nil {
+			objects[key] = &snapshotObject{kind: kind, name: name}
+		}
+		return objects[key]
+	}
+	query := func(q string, fn func(row []string)) error {
+		rows, err := queryStrings(ctx, tx, q)
+		if err != nil {
+			return fmt.Errorf("failed to capture schema snapshot: %w", err)
+		}
+		for _, row := range rows {
+			fn(row)
+		}
+		return nil
+	}
+
+	steps := []struct {
+		query string
+		fn    func(row []string)
+	}{
+		{`SELECT quote_ident(e.extname), e.extversion, quote_ident(n.nspname)
+		  FROM pg_extension e JOIN pg_namespace n ON n.oid = e.extnamespace`,
+			func(r []string) { object("extension", r[0]).header = "version " + r[1] + " schema " + r[2] }},
+
+		{`SELECT quote_ident(n.nspname) FROM pg_namespace n WHERE ` + userSchemaFilter,
+			func(r []string) { object("schema", r[0]) }},
+
+		{`SELECT quote_ident(n.nspname) || '.' || quote_ident(t.typname),
+		         string_agg(quote_literal(e.enumlabel), ', ' ORDER BY e.enumsortorder)
+		  FROM pg_type t
+		  JOIN pg_namespace n ON n.oid = t.typnamespace
+		  JOIN pg_enum e ON e.enumtypid = t.oid
+		  WHERE ` + userSchemaFilter + ` AND ` + fmt.Sprintf(notExtensionMember, "pg_type", "t.oid") + `
+		  GROUP BY 1`,
+			func(r []string) { object("enum", r[0]).header = "(" + r[1] + ")" }},
+
+		{`SELECT quote_ident(n.nspname) || '.' || quote_ident(c.relname), format_type(s.seqtypid, NULL),
+		         s.seqstart::text, s.seqincrement::text, s.seqmin::text, s.seqmax::text, s.seqcache::text, s.seqcycle::text,
+		         (SELECT quote_ident(dn.nspname) || '.' || quote_ident(dc.relname) || '.' || quote_ident(a.attname)
+		          FROM pg_depend d
+		          JOIN pg_class dc ON dc.oid = d.refobjid
+		          JOIN pg_namespace dn ON dn.oid = dc.relnamespace
+		          JOIN pg_attribute a ON a.attrelid = d.refobjid AND a.attnum = d.refobjsubid
+		          WHERE d.classid = 'pg_class'::regclass AND d.objid = c.oid AND d.deptype IN ('a', 'i')
+		          LIMIT 1)
+		  FROM pg_sequence s
+		  JOIN pg_class c ON c.oid = s.seqrelid
+		  JOIN pg_namespace n ON n.oid = c.relnamespace
+		  WHERE ` + userSchemaFilter + ` AND ` + fmt.Sprintf(notExtensionMember, "pg_class", "c.oid"),
+			func(r []string) {
+				header := fmt.Sprintf("as %s start %s increment %s min %s max %s cache %s", r[1], r[2], r[3], r[4], r[5], r[6])
+				if r[7] == "true" {
+					header += " cycle"
+				}
+				if r[8] != "" {
+					header += " owned by " + r[8]
+				}
+				object("sequence", r[0]).header = header
+			}},
+
+		{`SELECT quote_ident(n.nspname) || '.' || quote_ident(c.relname), c.relkind::text,
+		         CASE WHEN c.relkind IN ('v', 'm') THEN pg_get_viewdef(c.oid) END
+		  FROM pg_class c
+		  JOIN pg_namespace n ON n.oid = c.relnamespace
+		  WHERE c.relkind IN ('r', 'p', 'v', 'm') AND ` + userSchemaFilter + ` AND ` + fmt.Sprintf(notExtensionMember, "pg_class", "c.oid"),
+			func(r []string) {
+				obj := object(relationKind(r[1]), r[0])
+				if r[2] != "" {
+					obj.lines = append(obj.lines, definitionLines(r[2])...)
+				}
+			}},
+
+		{`SELECT quote_ident(n.nspname) || '.' || quote_ident(c.relname), c.relkind::text, quote_ident(a.attname),
+		         format_type(a.atttypid, a.atttypmod), a.attnotnull::text, pg_get_expr(ad.adbin, ad.adrelid),
+		         a.attidentity::text, a.attgenerated::text,
+		         CASE WHEN a.attcollation <> t.typcollation THEN (SELECT quote_ident(collname) FROM pg_collation WHERE oid = a.attcollation) END
+		  FROM pg_attribute a
+		  JOIN pg_class c ON c.oid = a.attrelid
+		  JOIN pg_namespace n ON n.oid = c.relnamespace
+		  JOIN pg_type t ON t.oid = a.atttypid
+		  LEFT JOIN pg_attrdef ad ON ad.adrelid = a.attrelid AND ad.adnum = a.attnum
+		  WHERE c.relkind IN ('r', 'p') AND a.attnum > 0 AND NOT a.attisdropped
+		    AND ` + userSchemaFilter + ` AND ` + fmt.Sprintf(notExtensionMember, "pg_class", "c.oid") + `
+		  ORDER BY a.attnum`,
+			func(r []string) {
+				obj := object(relationKind(r[1]), r[0])
+				obj.lines = append(obj.lines, columnDefinition(r[2:]))
+			}},
+
+		{`SELECT quote_ident(n.nspname) || '.' || quote_ident(c.relname), c.relkind::text,
+		         quote_ident(con.conname), pg_get_constraintdef(con.oid)
+		  FROM pg_constraint con
+		  JOIN pg_class c ON c.oid = con.conrelid
+		  JOIN pg_namespace n ON n.oid = c.relnamespace
+		  WHERE ` + userSchemaFilter + ` AND ` + fmt.Sprintf(notExtensionMember, "pg_class", "c.oid"),
+			func(r []string) {
+				obj := object(relationKind(r[1]), r[0])
+				obj.lines = append(obj.lines, "constraint "+r[2]+" "+r[3])
+			}},
+
+		{`SELECT quote_ident(n.nspname) || '.' || quote_ident(c.relname), c.relkind::text, pg_get_indexdef(i.oid)
+		  FROM pg_index x
+		  JOIN pg_class i ON i.oid = x.indexrelid
+		  JOIN pg_class c ON c.oid = x.indrelid
+		  JOIN pg_namespace n ON n.oid = c.relnamespace
+		  WHERE c.relkind IN ('r', 'p', 'm') AND ` + userSchemaFilter + ` AND ` + fmt.Sprintf(notExtensionMember, "pg_class", "c.oid"),
+			func(r []string) {
+				obj := object(relationKind(r[1]), r[0])
+				obj.lines = append(obj.lines, "index "+r[2])
+			}},
+
+		{`SELECT quote_ident(n.nspname) || '.' || quote_ident(c.relname), c.relkind::text, pg_get_triggerdef(t.oid)
+		  FROM pg_trigger t
+		  JOIN pg_class c ON c.oid = t.tgrelid
+		  JOIN pg_namespace n ON n.oid = c.relnamespace
+		  WHERE NOT t.tgisinternal AND ` + userSchemaFilter + ` AND ` + fmt.Sprintf(notExtensionMember, "pg_class", "c.oid"),
+			func(r []string) {
+				obj := object(relationKind(r[1]), r[0])
+				obj.lines = append(obj.lines, "trigger "+r[2])
+			}},
+
+		{`SELECT quote_ident(n.nspname) || '.' || quote_ident(p.proname) || '(' || pg_get_function_identity_arguments(p.oid) || ')',
+		         pg_get_functiondef(p.oid)
+		  FROM pg_proc p
+		  JOIN pg_namespace n ON n.oid = p.pronamespace
+		  WHERE p.prokind IN ('f', 'p') AND ` + userSchemaFilter + ` AND ` + fmt.Sprintf(notExtensionMember, "pg_proc", "p.oid"),
+			func(r []string) {
+				obj := object("function", r[0])
+				obj.lines = append(obj.lines, definitionLines(r[1])...)
+			}},
+	}
+	for _, step := range steps {
+		if err := query(step.query, step.fn); err != nil {
+			return "", err
+		}
+	}
+
+	return formatSnapshot(objects), nil
+}
+
+// 스냅샷에 출력되는 객체 종류의 순서
+var snapshotKinds = []string{"extension", "schema", "enum", "sequence", "table", "view", "materialized view", "function"}
+
+// formatSnapshot 객체를 종류와 이름 순으로 정렬하여 텍스트로 만듭니다.
+// 테이블의 컬럼은 정의된 순서를 유지하고, 제약 조건, 인덱스, 트리거는 정렬합니다.
+func formatSnapshot(objects map[string]*snapshotObject) string {
+	sorted := make([]*snapshotObject, 0, len(objects))
+	for _, obj := range objects {
+		sorted = append(sorted, obj)
+	}
+	slices.SortFunc(sorted, func(a, b *snapshotObject) int {
+		if c := slices.Index(snapshotKinds, a.kind) - slices.Index(snapshotKinds, b.kind); c != 0 {
+			return c
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	var b strings.Builder
+	for _, obj := range sorted {
+		b.WriteString(obj.kind + " " + obj.name)
+		if obj.header != "" {
+			b.WriteString(" " + obj.header)
+		}
+		b.WriteString("\n")
+
+		lines := obj.lines
+		if obj.kind == "table" {
+			// 컬럼은 쿼리 순서(attnum)를 유지하고 나머지 항목만 정렬
+			columns := slices.DeleteFunc(slices.Clone(lines), func(l string) bool { return !strings.HasPrefix(l, "column ") })
+			others := slices.DeleteFunc(slices.Clone(lines), func(l string) bool { return strings.HasPrefix(l, "column ") })
+			slices.Sort(others)
+			lines = append(columns, others...)
+		} else if obj.kind == "materialized view" {
+			// 정의 뒤에 오는 인덱스만 정렬
+			def := slices.DeleteFunc(slices.Clone(lines), func(l string) bool { return strings.HasPrefix(l, "index ") })
+			indexes := slices.DeleteFunc(slices.Clone(lines), func(l string) bool { return !strings.HasPrefix(l, "index ") })
+			slices.Sort(indexes)
+			lines = append(def, indexes...)
+		}
+		for _, line := range lines {
+			b.WriteString("    " + line + "\n")
+		}
+	}
+	return b.String()
+}
+
+// relationKind pg_class.relkind를 스냅샷의 객체 종류로 변환합니다.
+func relationKind(relkind string) string {
+	switch relkind {
+	case "v":
+		return "view"
+	case "m":
+		return "materialized view"
+	default:
+		return "table"
+	}
+}
+
+// columnDefinition 컬럼 정보(이름, 타입, NOT NULL, 기본값, identity, generated, collation)를 한 줄로 만듭니다.
+func columnDefinition(c []string) string {
+	def := "column " + c[0] + " " + c[1]
+	if c[6] != "" {
+		def += " collate " + c[6]
+	}
+	if c[2] == "true" {
+		def += " not null"
+	}
+	switch {
+	case c[5] == "s":
+		def += " generated always as (" + c[3] + ") stored"
+	case c[4] == "a":
+		def += " generated always as identity"
+	case c[4] == "d":
+		def += " generated by default as identity"
+	case c[3] != "":
+		def += " default " + c[3]
+	}
+	return def
+}
+
+// definitionLines 뷰나 함수 정의를 줄 단위로 나누고 끝의 공백과 빈 줄을 제거합니다.
+func definitionLines(def string) []string {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(def), "\n") {
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+	return lines
+}
@@ -0,0 +1,44 @@
+package pgtestkit
+
+import "testing"
+
+func TestFormatSnapshot(t *testing.T) {
+	objects := map[string]*snapshotObject{
+		"function public.f()": {kind: "function", name: "public.f()", lines: []string{"CREATE FUNCTION public.f()"}},
+		"table public.b": {kind: "table", name: "public.b", lines: []string{
+			"column id integer not null",
+			"index CREATE UNIQUE INDEX b_pkey ON public.b USING btree (id)",
+			"column a text",
+			"constraint b_pkey PRIMARY KEY (id)",
+		}},
+		"table public.a": {kind: "table", name: "public.a"},
+		"schema public":  {kind: "schema", name: "public"},
+	}
+
+	want := `schema public
+table public.a
+table public.b
+    column id integer not null
+    column a text
+    constraint b_pkey PRIMARY KEY (id)
+    index CREATE UNIQUE INDEX b_pkey ON public.b USING btree (id)
+function public.f()
+    CREATE FUNCTION public.f()
+`
+	if got := formatSnapshot(objects); got != want {
+		t.Errorf("formatSnapshot() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestColumnDefinition(t *testing.T) {
+	tests := map[string][]string{
+		"column id integer not null generated always as identity": {"id", "integer", "true", "", "a", "", ""},
+		"column n text collate \"C\" default 'x'::text":           {"n", "text", "false", "'x'::text", "", "", `"C"`},
+		"column t integer generated always as ((a * 2)) stored":   {"t", "integer", "false", "(a * 2)", "", "s", ""},
+	}
+	for want, column := range tests {
+		if got := columnDefinition(column); got != want {
+			t.Errorf("columnDefinition() = %q, want %q", got, want)
+		}
+	}
+}
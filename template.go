@@ -0,0 +1,281 @@
+package pgtestkit
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+var (
+	// 기본 템플릿 데이터베이스 이름 (serverMutex로 보호)
+	defaultTemplate string
+
+	// CreateTemplateDB로 만든 템플릿 데이터베이스 이름 (serverMutex로 보호)
+	createdTemplates []string
+)
+
+// Option CreateTestDB의 동작을 변경하는 옵션입니다.
+type Option func(*dbOptions)
+
+// dbOptions 테스트 데이터베이스 생성 옵션을 저장합니다.
+type dbOptions struct {
+	template    string
+	useTemplate bool
+	nameHint    string
+
+	tb            testing.TB
+	keepOnFailure *bool
+
+	txIsolation bool
+
+	schemaIsolation   bool
+	templateSchema    string
+	useTemplateSchema bool
+
+	migrator Migrator
+
+	resetStrategy ResetStrategy
+
+	serverLogs *bool
+}
+
+// WithTemplate 지정한 템플릿 데이터베이스를 복제하여 테스트 데이터베이스를 생성합니다.
+// 빈 문자열을 전달하면 기본 템플릿이 등록되어 있더라도 빈 데이터베이스를 생성합니다.
+func WithTemplate(name string) Option {
+	return func(o *dbOptions) {
+		o.template = name
+		o.useTemplate = true
+	}
+}
+
+// WithNameHint 테스트 데이터베이스 이름에 hint를 포함합니다.
+// 영문 소문자, 숫자 외의 문자는 밑줄로 바뀌고, 길이 제한을 넘는 부분은 잘립니다.
+func WithNameHint(hint string) Option {
+	return func(o *dbOptions) {
+		o.nameHint = hint
+	}
+}
+
+// newDBOptions 옵션을 적용하고, 템플릿이 지정되지 않았다면 기본 템플릿을 사용합니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func newDBOptions(opts []Option) dbOptions {
+	var o dbOptions
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&o)
+		}
+	}
+	if !o.useTemplate {
+		o.template = defaultTemplate
+	}
+	if !o.useTemplateSchema {
+		o.templateSchema = defaultTemplateSchema
+	}
+	if o.keepOnFailure == nil {
+		keep := envBool(KeepOnFailureEnv)
+		o.keepOnFailure = &keep
+	}
+	if o.serverLogs == nil {
+		enabled := true
+		o.serverLogs = &enabled
+	}
+	return o
+}
+
+// SetDefaultTemplate CreateTestDB가 기본으로 복제할 템플릿 데이터베이스를 등록합니다.
+// 빈 문자열을 전달하면 등록을 해제합니다.
+func SetDefaultTemplate(name string) error {
+	serverMutex.Lock()
+	defer serverMutex.Unlock()
+
+	if name == "" {
+		setDefaultTemplate("")
+		return nil
+	}
+
+	if !serverStarted || serverStopped {
+		err := serverNotRunningError()
+		logError("Cannot register template database", err)
+		return err
+	}
+
+	var exists bool
+	err := baseDBClient.QueryRow(
+		"SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", name).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check if template database exists: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("template database %s does not exist", name)
+	}
+
+	setDefaultTemplate(name)
+	getLogger().Info("Registered default template database", zap.String("template", name))
+	return nil
+}
+
+// CreateTemplateDB 템플릿 데이터베이스를 생성하고 setup 함수로 초기화한 뒤 기본 템플릿으로 등록합니다.
+// setup에는 템플릿 데이터베이스의 연결 문자열이 전달되며, 마이그레이션과 참조 데이터 적재를 수행하면 됩니다.
+// setup이 연 연결은 반환 전에 모두 닫아야 하지만, 남아 있는 연결은 등록 직전에 강제로 종료됩니다.
+//
+// 이 프로세스가 앞서 같은 이름으로 만든 템플릿은 삭제한 뒤 다시 만들어 setup을 실행합니다.
+// 다른 프로세스의 템플릿이거나 중단된 이전 실행이 남긴 데이터베이스처럼 이 프로세스가 만들지 않은 데이터베이스가
+// 이미 있으면, 다른 프로세스가 복제 중인 템플릿을 지우거나 오래된 스키마를 재사용하지 않도록 ErrTemplateExists를 반환합니다.
+// 공유 서버(WithSharedServer)나 외부 서버(WithDatabaseURL)를 사용한다면 패키지마다 다른 템플릿 이름을 사용하세요.
+//
+// 사용 예시:
+//
+//	func TestMain(m *testing.M) {
+//		if err := pgtestkit.StartEmbeddedPostgres(nil); err != nil {
+//			log.Fatal(err)
+//		}
+//		if err := pgtestkit.CreateTemplateDB("golden", runMigrations); err != nil {
+//			log.Fatal(err)
+//		}
+//		os.Exit(pgtestkit.TestMainWrapper(m, nil))
+//	}
+func CreateTemplateDB(name string, setup func(connString string) error) error {
+	return CreateTemplateDBContext(context.Background(), name, setup)
+}
+
+// CreateTemplateDBContext 컨텍스트를 지원하는 CreateTemplateDB입니다.
+// 컨텍스트는 데이터베이스 생성과 정리에 사용되며, setup 함수에는 전달되지 않습니다.
+func CreateTemplateDBContext(ctx context.Context, name string, setup func(connString string) error) error {
+	if name == "" {
+		return fmt.Errorf("template database name cannot be empty")
+	}
+
+	logger := getLogger().With(zap.String("template", name))
+	logger.Info("Creating template database")
+
+	serverMutex.Lock()
+	if !serverStarted || serverStopped {
+		err := serverNotRunningError()
+		serverMutex.Unlock()
+		logError("Cannot create template database", err)
+		return err
+	}
+	if err := dropExistingTemplate(ctx, name, logger); err != nil {
+		serverMutex.Unlock()
+		logError("Cannot create template database", err)
+		return err
+	}
+	if err := createDatabase(ctx, name, ""); err != nil {
+		serverMutex.Unlock()
+		return fmt.Errorf("failed to create template database: %w", err)
+	}
+	serverMutex.Unlock()
+
+	// 마이그레이션 등 초기화 작업은 오래 걸릴 수 있으므로 잠금 없이 수행
+	if setup != nil {
+		if err := setup(getConnectionString(name)); err != nil {
+			logError("Template setup failed, dropping template database", err)
+			serverMutex.Lock()
+			if dropErr := dropDatabase(context.WithoutCancel(ctx), name); dropErr != nil {
+				logError("Failed to drop template database after setup error", dropErr)
+			}
+			serverMutex.Unlock()
+			return fmt.Errorf("failed to set up template database %s: %w", name, err)
+		}
+	}
+
+	serverMutex.Lock()
+	defer serverMutex.Unlock()
+
+	// 템플릿에 연결이 남아 있으면 복제가 실패하므로 미리 정리
+	if err := terminateConnections(ctx, name); err != nil {
+		logger.Warn("Failed to terminate connections to template database", zap.Error(err))
+	}
+
+	setDefaultTemplate(name)
+	if !slices.Contains(createdTemplates, name) {
+		createdTemplates = append(createdTemplates, name)
+	}
+	logger.Info("Successfully created template database")
+	return nil
+}
+
+// dropExistingTemplate 이 프로세스가 같은 이름으로 만든 템플릿 데이터베이스를 삭제합니다.
+// 기본 템플릿이었다면 등록을 해제하여 데이터베이스 풀이 이전 템플릿으로 복제한 데이터베이스를 버리도록 합니다.
+// 이 프로세스가 만들지 않은 데이터베이스가 있으면 ErrTemplateExists를 반환합니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func dropExistingTemplate(ctx context.Context, name string, logger *zap.Logger) error {
+	var exists bool
+	if err := baseDBClient.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM pg_database WHERE datname = $1)", name).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to check if template database exists: %w", err)
+	}
+	if !exists {
+		return nil
+	}
+	if !slices.Contains(createdTemplates, name) {
+		return fmt.Errorf("%w: %s was not created by this process; use a different template name per package, "+
+			"or drop the database left over from an earlier run with DROP DATABASE %s WITH (FORCE)",
+			ErrTemplateExists, name, quoteIdentifier(name))
+	}
+
+	logger.Info("Recreating template database created earlier by this process")
+	if defaultTemplate == name {
+		setDefaultTemplate("")
+	}
+	if err := dropDatabase(ctx, name); err != nil {
+		return fmt.Errorf("failed to drop existing template database: %w", err)
+	}
+	createdTemplates = slices.DeleteFunc(createdTemplates, func(created string) bool { return created == name })
+	return nil
+}
+
+// dropCreatedTemplates CreateTemplateDB로 만든 템플릿 데이터베이스를 모두 삭제합니다.
+// 외부 서버의 데이터베이스는 서버와 함께 사라지지 않으므로 StopPostgres에서 호출합니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func dropCreatedTemplates(ctx context.Context) []error {
+	var errs []error
+	for _, name := range createdTemplates {
+		if err := dropDatabase(ctx, name); err != nil {
+			errs = append(errs, fmt.Errorf("failed to drop template database %s: %w", name, err))
+		}
+	}
+	createdTemplates = nil
+	setDefaultTemplate("")
+	return errs
+}
+
+// setDefaultTemplate 기본 템플릿을 변경하고 데이터베이스 풀에 알립니다.
+// 호출자는 serverMutex를 보유하고 있어야 합니다.
+func setDefaultTemplate(name string) {
+	defaultTemplate = name
+	if dbPool != nil {
+		dbPool.setTemplate(name)
+	}
+}
+
+// terminateConnections 지정한 데이터베이스에 연결된 다른 세션을 강제로 종료합니다.
+func terminateConnections(ctx context.Context, dbName string) error {
+	if baseDBClient == nil {
+		return fmt.Errorf("base database client is not initialized")
+	}
+
+	_, err := baseDBClient.ExecContext(ctx,
+		`SELECT pg_terminate_backend(pid)
+		 FROM pg_stat_activity
+		 WHERE datname = $1
+		 AND pid <> pg_backend_pid()`, dbName)
+	if err != nil {
+		return fmt.Errorf("failed to terminate connections to %s: %w", dbName, err)
+	}
+	return nil
+}
+
+// isTemplateInUseError 템플릿 데이터베이스에 다른 세션이 연결되어 복제가 실패했는지 확인합니다.
+func isTemplateInUseError(err error) bool {
+	return SQLState(err) == sqlStateObjectInUse
+}
+
+// quoteIdentifier PostgreSQL 식별자를 안전하게 이스케이프합니다.
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,66 @@
+package pgtestkit
+
+import (
+	"testing"
+)
+
+// New 테스트 데이터베이스를 생성하고, 테스트가 끝나면 자동으로 정리되도록 t.Cleanup에 등록합니다.
+// 생성에 실패하면 t.Fatalf로 테스트를 즉시 중단하며, 정리 중 발생한 에러는 t.Errorf로 보고합니다.
+// 데이터베이스 이름에는 t.Name()이 포함되어 어떤 테스트의 데이터베이스인지 쉽게 알 수 있습니다.
+// WithKeepOnFailure 옵션이나 PGTESTKIT_KEEP_ON_FAILURE 환경 변수로 실패한 테스트의 데이터베이스를 보존할 수 있습니다.
+//
+// 사용 예시:
+//
+//	func TestSomething(t *testing.T) {
+//		dbClient, helper := pgtestkit.New(t, &SQLConnector{})
+//		db := dbClient.Client.(*sql.DB)
+//		helper.MustResetDB(t)
+//		// ... 테스트 코드 ...
+//	}
+func New(t testing.TB, connector DBConnector, opts ...Option) (*DBClient, *TestHelper) {
+	t.Helper()
+
+	opts = append([]Option{WithNameHint(t.Name()), WithTB(t)}, opts...)
+	dbClient, err := CreateTestDB(connector, opts...)
+	if err != nil {
+		t.Fatalf("pgtestkit: failed to create test database: %v", err)
+	}
+
+	helper := NewTestHelper(dbClient)
+	registerCleanup(t, dbClient, helper)
+	return dbClient, helper
+}
+
+// NewOf 타입이 지정된 커넥터를 사용하는 New입니다.
+//
+// 사용 예시:
+//
+//	func TestSomething(t *testing.T) {
+//		dbClient, helper := pgtestkit.NewOf(t, pgtestkit.TypedConnector[*gorm.DB](&GORMConnector{}))
+//		gormDB := dbClient.Client // *gorm.DB
+//		// ... 테스트 코드 ...
+//	}
+func NewOf[T any](t testing.TB, connector Connector[T], opts ...Option) (*TypedDBClient[T], *TestHelper) {
+	t.Helper()
+
+	opts = append([]Option{WithNameHint(t.Name()), WithTB(t)}, opts...)
+	dbClient, err := CreateTestDBOf(connector, opts...)
+	if err != nil {
+		t.Fatalf("pgtestkit: failed to create test database: %v", err)
+	}
+
+	helper := NewTestHelper(dbClient.DBClient)
+	registerCleanup(t, dbClient.DBClient, helper)
+	return dbClient, helper
+}
+
+// registerCleanup 테스트 헬퍼와 DB 클라이언트를 정리하는 함수를 등록합니다.
+// 커넥터는 dbClient.Close가 한 번만 닫으므로, 헬퍼의 Close 대신 Assert* 연결만 먼저 닫습니다.
+func registerCleanup(t testing.TB, dbClient *DBClient, helper *TestHelper) {
+	t.Cleanup(func() {
+		helper.closeAssertDB()
+		if err := dbClient.Close(); err != nil {
+			t.Errorf("pgtestkit: failed to close test database %s: %v", dbClient.DBName, err)
+		}
+	})
+}
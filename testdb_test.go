@@ -0,0 +1,23 @@
+package pgtestkit
+
+import "testing"
+
+// countingConnector Close 호출 횟수를 세는 커넥터입니다.
+type countingConnector struct{ closes int }
+
+func (c *countingConnector) Connect(string) (interface{}, error) { return nil, nil }
+func (c *countingConnector) Close() error                        { c.closes++; return nil }
+func (c *countingConnector) Reset() error                        { return nil }
+
+func TestRegisterCleanupClosesConnectorOnce(t *testing.T) {
+	connector := &countingConnector{}
+	dbClient := &DBClient{connector: connector}
+
+	t.Run("test", func(t *testing.T) {
+		registerCleanup(t, dbClient, NewTestHelper(dbClient))
+	})
+
+	if connector.closes != 1 {
+		t.Errorf("Expected the connector to be closed once, got %d", connector.closes)
+	}
+}
@@ -0,0 +1,745 @@
+package pgtestkit
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.uber.org/zap"
+)
+
+const (
+	// TxDriverName 트랜잭션 격리 모드의 연결 문자열을 여는 database/sql 드라이버 이름입니다.
+	// WithTxIsolation으로 만든 DBClient의 ConnectionString은 이 드라이버로만 열 수 있습니다.
+	//
+	// 예: sql.Open(pgtestkit.TxDriverName, connString)
+	TxDriverName = "pgtestkit-tx"
+
+	// 트랜잭션 격리 모드 연결 문자열의 접두사
+	txDSNPrefix = TxDriverName + ":"
+
+	// 테스트 시작 시점을 표시하는 세이브포인트 (Reset은 여기로 되돌림)
+	txBaseSavepoint = "pgtestkit_base"
+
+	// 트랜잭션 밖의 문장이 실패해도 테스트 트랜잭션이 중단되지 않도록 감싸는 세이브포인트
+	txStatementSavepoint = "pgtestkit_stmt"
+)
+
+var (
+	// 열려 있는 테스트 트랜잭션 (연결 문자열 ID별)
+	txSessions   sync.Map
+	txSessionSeq atomic.Uint64
+)
+
+func init() {
+	sql.Register(TxDriverName, txDriver{})
+}
+
+// WithTxIsolation 테스트마다 데이터베이스를 만들지 않고, 공유 데이터베이스의 트랜잭션 하나로 테스트를 격리합니다.
+// 공유 데이터베이스는 템플릿(WithTemplate 또는 SetDefaultTemplate)별로 처음 한 번만 복제되며,
+// 테스트의 모든 변경은 Close에서 항상 롤백됩니다. 애플리케이션 코드가 실행하는 BEGIN/COMMIT/ROLLBACK은
+// 세이브포인트로 바뀌므로 트랜잭션을 사용하는 코드도 그대로 테스트할 수 있습니다.
+//
+// 커넥터에는 실제 연결 문자열 대신 TxDriverName 드라이버로 여는 연결 문자열이 전달됩니다.
+// TxSQLConnector(database/sql)와 TxPgxConnector(pgx.Tx)를 사용하거나, 직접 만든 커넥터에서
+// sql.Open(pgtestkit.TxDriverName, connString)으로 연결하세요.
+//
+// 같은 데이터베이스를 공유하므로 병렬 테스트가 같은 행을 수정하면 잠금을 기다릴 수 있습니다.
+func WithTxIsolation() Option {
+	return func(o *dbOptions) {
+		o.txIsolation = true
+	}
+}
+
+// txSession 테스트 하나가 사용하는 연결과 트랜잭션입니다.
+type txSession struct {
+	id     string
+	dbName string
+	conn   *stdlib.Conn
+	tx     pgx.Tx
+
+	mu         sync.Mutex
+	savepoints []string // 애플리케이션이 시작한 트랜잭션(세이브포인트)을 시작한 순서대로 저장
+	seq        int      // 세이브포인트 이름을 만들기 위한 순번
+	closed     bool
+}
+
+// createTxTestDB 트랜잭션 격리 모드의 테스트 데이터베이스 클라이언트를 생성합니다.
+// serverMutex는 공유 데이터베이스를 준비하고 트랜잭션을 시작하는 동안에만 보유합니다.
+func createTxTestDB(ctx context.Context, connector DBConnector, options dbOptions, logger *zap.Logger) (*DBClient, error) {
+	serverMutex.Lock()
+	shared, err := getSharedDatabase(ctx, "tx", options.template, logger)
+	if err != nil {
+		serverMutex.Unlock()
+		logError("Failed to prepare shared transaction database", err)
+		return nil, err
+	}
+	logger = logger.With(zap.String("database", shared.name))
+
+	session, err := openTxSession(ctx, shared.name)
+	serverMutex.Unlock()
+	if err != nil {
+		logError("Failed to begin test transaction", err)
+		return nil, err
+	}
+
+	connString := txDSNPrefix + session.id
+	client, err := connectWithRetry(ctx, connector, connString, logger)
+	if err != nil {
+		session.close(context.WithoutCancel(ctx))
+		return nil, fmt.Errorf("failed to connect to test transaction: %w", err)
+	}
+
+	if err := resetWithRetry(ctx, connector, logger); err != nil {
+		if closeErr := connector.Close(); closeErr != nil {
+			logError("Failed to close connector after reset error", closeErr)
+		}
+		session.close(context.WithoutCancel(ctx))
+		return nil, fmt.Errorf("failed to reset test transaction: %w", err)
+	}
+
+	logger.Info("Successfully started test transaction", zap.String("session", session.id))
+	return &DBClient{
+		Client:           client,
+		DBName:           shared.name,
+		ConnectionString: connString,
+		connector:        connector,
+		tb:               options.tb,
+		tx:               session,
+	}, nil
+}
+
+// openTxSession 데이터베이스에 새 연결을 열고 테스트 트랜잭션을 시작합니다.
+func openTxSession(ctx context.Context, dbName string) (*txSession, error) {
+	connector, err := stdlib.GetDefaultDriver().(driver.DriverContext).OpenConnector(getConnectionString(dbName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open connector: %w", err)
+	}
+	conn, err := connector.Connect(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", dbName, err)
+	}
+	stdConn := conn.(*stdlib.Conn)
+
+	tx, err := stdConn.Conn().Begin(ctx)
+	if err != nil {
+		stdConn.Close()
+		return nil, fmt.Errorf("failed to begin test transaction: %w", err)
+	}
+	if _, err := tx.Exec(ctx, "SAVEPOINT "+txBaseSavepoint); err != nil {
+		stdConn.Close()
+		return nil, fmt.Errorf("failed to create base savepoint: %w", err)
+	}
+
+	session := &txSession{
+		id:     fmt.Sprintf("%s/%d", dbName, txSessionSeq.Add(1)),
+		dbName: dbName,
+		conn:   stdConn,
+		tx:     tx,
+	}
+	txSessions.Store(session.id, session)
+	return session, nil
+}
+
+// lookupTxSession 연결 문자열에 해당하는 테스트 트랜잭션을 찾습니다.
+func lookupTxSession(connString string) (*txSession, error) {
+	id, ok := strings.CutPrefix(connString, txDSNPrefix)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a %s connection string; create the database with WithTxIsolation", connString, TxDriverName)
+	}
+	value, ok := txSessions.Load(id)
+	if !ok {
+		return nil, fmt.Errorf("test transaction %s is closed or does not exist", id)
+	}
+	return value.(*txSession), nil
+}
+
+// exec 테스트 트랜잭션에서 제어 문장을 실행합니다. 호출자는 s.mu를 보유하고 있어야 합니다.
+func (s *txSession) exec(ctx context.Context, query string) error {
+	if s.closed {
+		return driver.ErrBadConn
+	}
+	_, err := s.conn.Conn().Exec(ctx, query)
+	return err
+}
+
+// statement 애플리케이션의 문장 하나를 실행합니다. 애플리케이션 트랜잭션 밖이면 자동 커밋처럼
+// 실패한 문장만 취소되도록 세이브포인트로 감쌉니다. 호출자는 s.mu를 보유하고 있어야 합니다.
+func (s *txSession) statement(ctx context.Context, run func() error) error {
+	if len(s.savepoints) > 0 {
+		return run()
+	}
+
+	if err := s.exec(ctx, "SAVEPOINT "+txStatementSavepoint); err != nil {
+		return err
+	}
+	if err := run(); err != nil {
+		if rbErr := s.exec(ctx, "ROLLBACK TO SAVEPOINT "+txStatementSavepoint); rbErr != nil {
+			return fmt.Errorf("%w (and failed to roll back statement: %v)", err, rbErr)
+		}
+		return err
+	}
+	return s.exec(ctx, "RELEASE SAVEPOINT "+txStatementSavepoint)
+}
+
+// begin 애플리케이션의 BEGIN을 세이브포인트로 에뮬레이션하고 세이브포인트 이름을 반환합니다.
+func (s *txSession) begin(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := fmt.Sprintf("pgtestkit_sp_%d", s.seq+1)
+	if err := s.exec(ctx, "SAVEPOINT "+name); err != nil {
+		return "", err
+	}
+	s.seq++
+	s.savepoints = append(s.savepoints, name)
+	return name, nil
+}
+
+// end 에뮬레이션된 트랜잭션을 커밋(RELEASE)하거나 롤백합니다.
+// name이 비어 있으면 가장 안쪽의 트랜잭션(SQL로 실행한 COMMIT/ROLLBACK)을 끝냅니다.
+// 모든 트랜잭션이 서버 연결 하나를 공유하므로, 나중에 시작한 트랜잭션이 남아 있는데 먼저 시작한 트랜잭션을
+// 끝내려고 하면 다른 트랜잭션의 변경까지 함께 커밋하거나 롤백하게 되어 에러를 반환합니다.
+func (s *txSession) end(ctx context.Context, name string, commit bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.savepoints) == 0 && name == "" {
+		// PostgreSQL도 트랜잭션 밖의 COMMIT/ROLLBACK은 경고만 남김
+		getLogger().Warn("Ignoring COMMIT/ROLLBACK outside of a transaction", zap.String("session", s.id))
+		return nil
+	}
+
+	i := len(s.savepoints) - 1
+	if name != "" {
+		i = slices.Index(s.savepoints, name)
+		if i < 0 {
+			return fmt.Errorf("transaction %s already ended or was rolled back by a reset", name)
+		}
+		if i != len(s.savepoints)-1 {
+			return fmt.Errorf("cannot end transaction %s while transaction %s started after it is still open: "+
+				"transactions share the test connection and must end in reverse order", name, s.savepoints[len(s.savepoints)-1])
+		}
+	}
+
+	name = s.savepoints[i]
+	if !commit {
+		if err := s.exec(ctx, "ROLLBACK TO SAVEPOINT "+name); err != nil {
+			return err
+		}
+	}
+	if err := s.exec(ctx, "RELEASE SAVEPOINT "+name); err != nil {
+		return err
+	}
+	s.savepoints = s.savepoints[:i]
+	return nil
+}
+
+// reset 테스트 트랜잭션을 시작 시점으로 되돌립니다.
+func (s *txSession) reset(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.exec(ctx, "ROLLBACK TO SAVEPOINT "+txBaseSavepoint); err != nil {
+		return fmt.Errorf("failed to roll back test transaction: %w", err)
+	}
+	s.savepoints = nil
+	return nil
+}
+
+// close 테스트 트랜잭션을 롤백하고 연결을 닫습니다. 여러 번 호출해도 안전합니다.
+func (s *txSession) close(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	txSessions.Delete(s.id)
+
+	var errs []error
+	if err := s.tx.Rollback(ctx); err != nil && err != pgx.ErrTxClosed {
+		errs = append(errs, fmt.Errorf("failed to roll back test transaction: %w", err))
+	}
+	if err := s.conn.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close test transaction connection: %w", err))
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%d error(s) occurred while closing test transaction: %w", len(errs), errors.Join(errs...))
+	}
+	return nil
+}
+
+// txDriver 연결 문자열의 테스트 트랜잭션에 묶인 연결을 여는 database/sql 드라이버입니다.
+type txDriver struct{}
+
+// Open driver.Driver를 구현합니다.
+func (txDriver) Open(name string) (driver.Conn, error) {
+	session, err := lookupTxSession(name)
+	if err != nil {
+		return nil, err
+	}
+	return &txConn{session: session}, nil
+}
+
+// txConn 테스트 트랜잭션 안에서 문장을 실행하는 database/sql 연결입니다.
+// 여러 txConn이 같은 트랜잭션을 공유할 수 있으며, 연결을 닫아도 트랜잭션은 유지됩니다.
+type txConn struct {
+	session *txSession
+}
+
+// txControl 트랜잭션 제어 문장의 종류를 반환합니다.
+func txControl(query string) string {
+	fields := strings.Fields(strings.ToUpper(strings.TrimSpace(strings.TrimRight(strings.TrimSpace(query), ";"))))
+	if len(fields) == 0 {
+		return ""
+	}
+	switch fields[0] {
+	case "BEGIN":
+		return "begin"
+	case "START":
+		if len(fields) > 1 && fields[1] == "TRANSACTION" {
+			return "begin"
+		}
+	case "COMMIT", "END":
+		return "commit"
+	case "ROLLBACK", "ABORT":
+		// ROLLBACK TO SAVEPOINT는 애플리케이션의 세이브포인트이므로 그대로 실행
+		if len(fields) == 1 || fields[1] == "WORK" || fields[1] == "TRANSACTION" {
+			return "rollback"
+		}
+	}
+	return ""
+}
+
+// Prepare driver.Conn을 구현합니다.
+func (c *txConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+// PrepareContext driver.ConnPrepareContext를 구현합니다.
+// 문장은 서버에서 한 번 준비하여 확인만 하고, 실행은 ExecContext와 QueryContext를 거치므로
+// 트랜잭션 제어 문장 처리와 세이브포인트 보호가 그대로 적용됩니다.
+func (c *txConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+
+	if c.session.closed {
+		return nil, driver.ErrBadConn
+	}
+	var numInput int
+	err := c.session.statement(ctx, func() error {
+		stmt, err := c.session.conn.PrepareContext(ctx, query)
+		if err != nil {
+			return err
+		}
+		numInput = stmt.NumInput()
+		return stmt.Close()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &txStmt{conn: c, query: query, numInput: numInput}, nil
+}
+
+// Close driver.Conn을 구현합니다. 테스트 트랜잭션은 DBClient.Close에서 롤백됩니다.
+func (c *txConn) Close() error {
+	return nil
+}
+
+// Begin driver.Conn을 구현합니다.
+func (c *txConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx driver.ConnBeginTx를 구현합니다.
+// 이미 테스트 트랜잭션 안이므로 격리 수준과 읽기 전용 옵션은 적용되지 않습니다.
+func (c *txConn) BeginTx(ctx context.Context, _ driver.TxOptions) (driver.Tx, error) {
+	name, err := c.session.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &txSavepoint{session: c.session, name: name}, nil
+}
+
+// ExecContext driver.ExecerContext를 구현합니다.
+func (c *txConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if len(args) == 0 {
+		switch txControl(query) {
+		case "begin":
+			_, err := c.session.begin(ctx)
+			return driver.RowsAffected(0), err
+		case "commit":
+			return driver.RowsAffected(0), c.session.end(ctx, "", true)
+		case "rollback":
+			return driver.RowsAffected(0), c.session.end(ctx, "", false)
+		}
+	}
+
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+
+	if c.session.closed {
+		return nil, driver.ErrBadConn
+	}
+	var result driver.Result
+	err := c.session.statement(ctx, func() error {
+		var err error
+		result, err = c.session.conn.ExecContext(ctx, query, args)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// QueryContext driver.QueryerContext를 구현합니다.
+// 결과를 모두 읽은 뒤 반환하므로, 실패한 쿼리도 세이브포인트로 취소되고
+// rows를 닫기 전에 같은 테스트 트랜잭션에서 다른 문장을 실행할 수 있습니다.
+func (c *txConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+
+	if c.session.closed {
+		return nil, driver.ErrBadConn
+	}
+	var rows *txRows
+	err := c.session.statement(ctx, func() error {
+		r, err := c.session.conn.QueryContext(ctx, query, args)
+		if err != nil {
+			return err
+		}
+		rows, err = bufferRows(r)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// Ping driver.Pinger를 구현합니다.
+func (c *txConn) Ping(ctx context.Context) error {
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+
+	if c.session.closed {
+		return driver.ErrBadConn
+	}
+	return c.session.conn.Ping(ctx)
+}
+
+// CheckNamedValue driver.NamedValueChecker를 구현합니다.
+func (c *txConn) CheckNamedValue(nv *driver.NamedValue) error {
+	return c.session.conn.CheckNamedValue(nv)
+}
+
+// IsValid driver.Validator를 구현합니다.
+func (c *txConn) IsValid() bool {
+	c.session.mu.Lock()
+	defer c.session.mu.Unlock()
+
+	return !c.session.closed
+}
+
+// txStmt 테스트 트랜잭션에서 실행되는 준비된 문장입니다.
+type txStmt struct {
+	conn     *txConn
+	query    string
+	numInput int
+}
+
+// Close driver.Stmt를 구현합니다.
+func (s *txStmt) Close() error {
+	return nil
+}
+
+// NumInput driver.Stmt를 구현합니다.
+func (s *txStmt) NumInput() int {
+	return s.numInput
+}
+
+// Exec driver.Stmt를 구현합니다.
+func (s *txStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+// ExecContext driver.StmtExecContext를 구현합니다.
+func (s *txStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+// Query driver.Stmt를 구현합니다.
+func (s *txStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+// QueryContext driver.StmtQueryContext를 구현합니다.
+func (s *txStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
+}
+
+// namedValues 위치 인자를 driver.NamedValue로 변환합니다.
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, arg := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: arg}
+	}
+	return named
+}
+
+// txColumn 읽어 둔 결과의 열 정보입니다.
+type txColumn struct {
+	name      string
+	dbType    string
+	scanType  reflect.Type
+	length    int64
+	hasLength bool
+	precision int64
+	scale     int64
+	hasScale  bool
+}
+
+// txRows 모두 읽어 둔 쿼리 결과입니다.
+type txRows struct {
+	columns []txColumn
+	values  [][]driver.Value
+	next    int
+}
+
+// bufferRows rows를 끝까지 읽어 txRows로 만들고 닫습니다.
+func bufferRows(rows driver.Rows) (*txRows, error) {
+	names := rows.Columns()
+	buffered := &txRows{columns: make([]txColumn, len(names))}
+	for i, name := range names {
+		column := txColumn{name: name}
+		if r, ok := rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+			column.dbType = r.ColumnTypeDatabaseTypeName(i)
+		}
+		if r, ok := rows.(driver.RowsColumnTypeScanType); ok {
+			column.scanType = r.ColumnTypeScanType(i)
+		}
+		if r, ok := rows.(driver.RowsColumnTypeLength); ok {
+			column.length, column.hasLength = r.ColumnTypeLength(i)
+		}
+		if r, ok := rows.(driver.RowsColumnTypePrecisionScale); ok {
+			column.precision, column.scale, column.hasScale = r.ColumnTypePrecisionScale(i)
+		}
+		buffered.columns[i] = column
+	}
+
+	for {
+		dest := make([]driver.Value, len(names))
+		err := rows.Next(dest)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		// 드라이버가 버퍼를 다시 사용할 수 있으므로 바이트 값은 복사
+		for i, value := range dest {
+			if b, ok := value.([]byte); ok {
+				dest[i] = bytes.Clone(b)
+			}
+		}
+		buffered.values = append(buffered.values, dest)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	return buffered, nil
+}
+
+// Columns driver.Rows를 구현합니다.
+func (r *txRows) Columns() []string {
+	names := make([]string, len(r.columns))
+	for i, column := range r.columns {
+		names[i] = column.name
+	}
+	return names
+}
+
+// Close driver.Rows를 구현합니다.
+func (r *txRows) Close() error {
+	r.values = nil
+	return nil
+}
+
+// Next driver.Rows를 구현합니다.
+func (r *txRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
+
+// ColumnTypeDatabaseTypeName driver.RowsColumnTypeDatabaseTypeName을 구현합니다.
+func (r *txRows) ColumnTypeDatabaseTypeName(index int) string {
+	return r.columns[index].dbType
+}
+
+// ColumnTypeScanType driver.RowsColumnTypeScanType을 구현합니다.
+func (r *txRows) ColumnTypeScanType(index int) reflect.Type {
+	if t := r.columns[index].scanType; t != nil {
+		return t
+	}
+	return reflect.TypeOf(new(any)).Elem()
+}
+
+// ColumnTypeLength driver.RowsColumnTypeLength를 구현합니다.
+func (r *txRows) ColumnTypeLength(index int) (int64, bool) {
+	return r.columns[index].length, r.columns[index].hasLength
+}
+
+// ColumnTypePrecisionScale driver.RowsColumnTypePrecisionScale을 구현합니다.
+func (r *txRows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	column := r.columns[index]
+	return column.precision, column.scale, column.hasScale
+}
+
+// txSavepoint 세이브포인트로 에뮬레이션된 애플리케이션 트랜잭션입니다.
+type txSavepoint struct {
+	session *txSession
+	name    string // 이 트랜잭션의 세이브포인트
+}
+
+// Commit driver.Tx를 구현합니다.
+func (t *txSavepoint) Commit() error {
+	return t.session.end(context.Background(), t.name, true)
+}
+
+// Rollback driver.Tx를 구현합니다.
+func (t *txSavepoint) Rollback() error {
+	return t.session.end(context.Background(), t.name, false)
+}
+
+// TxSQLConnector 트랜잭션 격리 모드의 테스트 트랜잭션에 묶인 *sql.DB를 제공하는 DBConnector입니다.
+// db.Begin 등으로 시작한 트랜잭션은 세이브포인트가 되며, Reset은 테스트 시작 시점으로 되돌립니다.
+// *sql.DB의 여러 연결은 서버 연결 하나를 공유하지만, 쿼리 결과를 모두 읽어 둔 뒤 반환하므로
+// rows.Next 반복 중에 db.Exec이나 db.BeginTx를 호출해도 됩니다.
+//
+// 연결을 공유하므로 sql.Tx가 열려 있는 동안 다른 고루틴이 실행한 문장은 그 트랜잭션의 세이브포인트 안에서 실행되고,
+// 트랜잭션을 롤백하면 함께 취소됩니다. 여러 sql.Tx는 시작한 역순으로 끝내야 하며, 나중에 시작한 트랜잭션이
+// 열려 있는 동안 먼저 시작한 트랜잭션의 Commit이나 Rollback은 에러를 반환합니다.
+//
+// 사용 예시:
+//
+//	dbClient, helper := pgtestkit.New(t, &pgtestkit.TxSQLConnector{}, pgtestkit.WithTxIsolation())
+//	db := dbClient.Client.(*sql.DB)
+type TxSQLConnector struct {
+	db         *sql.DB
+	connString string
+}
+
+// Connect DBConnector를 구현합니다.
+func (c *TxSQLConnector) Connect(connString string) (interface{}, error) {
+	return c.ConnectContext(context.Background(), connString)
+}
+
+// ConnectContext ContextDBConnector를 구현합니다.
+func (c *TxSQLConnector) ConnectContext(ctx context.Context, connString string) (interface{}, error) {
+	if _, err := lookupTxSession(connString); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open(TxDriverName, connString)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, err
+	}
+	c.db = db
+	c.connString = connString
+	return db, nil
+}
+
+// Close DBConnector를 구현합니다.
+func (c *TxSQLConnector) Close() error {
+	if c.db == nil {
+		return nil
+	}
+	err := c.db.Close()
+	c.db = nil
+	return err
+}
+
+// Reset DBConnector를 구현합니다.
+func (c *TxSQLConnector) Reset() error {
+	return c.ResetContext(context.Background())
+}
+
+// ResetContext ContextDBConnector를 구현합니다.
+func (c *TxSQLConnector) ResetContext(ctx context.Context) error {
+	session, err := lookupTxSession(c.connString)
+	if err != nil {
+		return err
+	}
+	return session.reset(ctx)
+}
+
+// TxPgxConnector 트랜잭션 격리 모드의 테스트 트랜잭션(pgx.Tx)을 제공하는 DBConnector입니다.
+// 반환되는 pgx.Tx는 TxSQLConnector와 같은 규칙을 따릅니다. Begin은 세이브포인트를 시작하고 그 트랜잭션의
+// Commit과 Rollback은 세이브포인트를 해제하거나 되돌리며, 트랜잭션 밖에서 실패한 문장은 그 문장만 취소됩니다.
+// 테스트 트랜잭션 자체의 Commit과 Rollback(그리고 트랜잭션 밖의 COMMIT 문장)은 무시되므로,
+// 테스트의 변경은 항상 DBClient.Close에서 롤백됩니다. Conn과 LargeObjects로 실행한 문장은 이 보호를 받지 않습니다.
+//
+// 사용 예시:
+//
+//	dbClient, helper := pgtestkit.NewOf(t, pgtestkit.TypedConnector[pgx.Tx](&pgtestkit.TxPgxConnector{}),
+//		pgtestkit.WithTxIsolation())
+//	tx := dbClient.Client // pgx.Tx
+type TxPgxConnector struct {
+	connString string
+}
+
+// Connect DBConnector를 구현합니다.
+func (c *TxPgxConnector) Connect(connString string) (interface{}, error) {
+	session, err := lookupTxSession(connString)
+	if err != nil {
+		return nil, err
+	}
+	c.connString = connString
+	return &txPgx{session: session}, nil
+}
+
+// ConnectContext ContextDBConnector를 구현합니다.
+func (c *TxPgxConnector) ConnectContext(_ context.Context, connString string) (interface{}, error) {
+	return c.Connect(connString)
+}
+
+// Close DBConnector를 구현합니다. 트랜잭션은 DBClient.Close에서 롤백됩니다.
+func (c *TxPgxConnector) Close() error {
+	return nil
+}
+
+// Reset DBConnector를 구현합니다.
+func (c *TxPgxConnector) Reset() error {
+	return c.ResetContext(context.Background())
+}
+
+// ResetContext ContextDBConnector를 구현합니다.
+func (c *TxPgxConnector) ResetContext(ctx context.Context) error {
+	session, err := lookupTxSession(c.connString)
+	if err != nil {
+		return err
+	}
+	return session.reset(ctx)
+}
@@ -0,0 +1,122 @@
+package pgtestkit
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestTxControl(t *testing.T) {
+	tests := map[string]string{
+		"BEGIN":                "begin",
+		"  begin transaction;": "begin",
+		"START TRANSACTION ISOLATION LEVEL SERIALIZABLE": "begin",
+		"COMMIT":                    "commit",
+		"end;":                      "commit",
+		"ROLLBACK":                  "rollback",
+		"rollback work":             "rollback",
+		"ROLLBACK TO SAVEPOINT sp1": "",
+		"SAVEPOINT sp1":             "",
+		"SELECT 1":                  "",
+		"":                          "",
+	}
+
+	for query, want := range tests {
+		if got := txControl(query); got != want {
+			t.Errorf("txControl(%q) = %q, want %q", query, got, want)
+		}
+	}
+}
+
+// fakeRows 정해진 값을 돌려주는 driver.Rows입니다.
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+	err     error // 모든 행을 돌려준 뒤 반환할 오류
+	closed  bool
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+
+func (r *fakeRows) Close() error {
+	r.closed = true
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		if r.err != nil {
+			return r.err
+		}
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func TestBufferRows(t *testing.T) {
+	buf := []byte("shared")
+	source := &fakeRows{
+		columns: []string{"id", "data"},
+		values:  [][]driver.Value{{int64(1), buf}, {int64(2), nil}},
+	}
+
+	rows, err := bufferRows(source)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !source.closed {
+		t.Error("Expected the source rows to be closed")
+	}
+
+	// 드라이버가 버퍼를 다시 사용해도 읽어 둔 값은 바뀌지 않아야 함
+	copy(buf, "reused")
+
+	var got [][]driver.Value
+	for {
+		dest := make([]driver.Value, 2)
+		if err := rows.Next(dest); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		got = append(got, dest)
+	}
+	if len(got) != 2 || string(got[0][1].([]byte)) != "shared" || got[1][1] != nil {
+		t.Errorf("Unexpected rows: %v", got)
+	}
+	if cols := rows.Columns(); len(cols) != 2 || cols[1] != "data" {
+		t.Errorf("Unexpected columns: %v", cols)
+	}
+
+	failing := &fakeRows{columns: []string{"id"}, values: [][]driver.Value{{int64(1)}}, err: errors.New("unique violation")}
+	if _, err := bufferRows(failing); err == nil || !failing.closed {
+		t.Errorf("Expected the row error to be returned and the rows closed, got %v", err)
+	}
+}
+
+func TestTxSessionEndOrder(t *testing.T) {
+	// 순서 확인은 서버에 문장을 보내기 전에 이루어짐
+	s := &txSession{id: "test", savepoints: []string{"pgtestkit_sp_1", "pgtestkit_sp_2"}}
+
+	err := s.end(context.Background(), "pgtestkit_sp_1", true)
+	if err == nil || !strings.Contains(err.Error(), "reverse order") {
+		t.Errorf("Expected an out-of-order error, got %v", err)
+	}
+	if err := s.end(context.Background(), "pgtestkit_sp_3", false); err == nil {
+		t.Error("Expected an error for a transaction that already ended")
+	}
+	if len(s.savepoints) != 2 {
+		t.Errorf("Expected rejected ends to keep the savepoints, got %v", s.savepoints)
+	}
+
+	// 트랜잭션 밖의 COMMIT은 무시됨
+	empty := &txSession{id: "test"}
+	if err := empty.end(context.Background(), "", true); err != nil {
+		t.Errorf("Expected COMMIT outside of a transaction to be ignored, got %v", err)
+	}
+}
@@ -0,0 +1,450 @@
+package pgtestkit
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// txPgx 테스트 트랜잭션을 pgx.Tx로 제공합니다. 모든 문장은 txSession을 거치므로 database/sql 드라이버와 같이
+// 트랜잭션 밖의 실패한 문장은 세이브포인트로 취소되고, Begin/Commit/Rollback은 세이브포인트가 됩니다.
+//
+// 테스트 트랜잭션 자체(savepoint가 빈 문자열)의 Commit과 Rollback은 아무것도 하지 않으며,
+// 변경은 DBClient.Close에서 롤백됩니다.
+type txPgx struct {
+	session   *txSession
+	savepoint string // Begin으로 시작한 트랜잭션의 세이브포인트 (테스트 트랜잭션이면 빈 문자열)
+	ended     bool   // Commit 또는 Rollback이 호출되었는지 여부 (session.mu로 보호)
+}
+
+// Begin pgx.Tx를 구현합니다. 세이브포인트로 중첩 트랜잭션을 시작합니다.
+func (t *txPgx) Begin(ctx context.Context) (pgx.Tx, error) {
+	if err := t.check(); err != nil {
+		return nil, err
+	}
+	name, err := t.session.begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &txPgx{session: t.session, savepoint: name}, nil
+}
+
+// Commit pgx.Tx를 구현합니다. Begin으로 시작한 트랜잭션이면 세이브포인트를 해제합니다.
+func (t *txPgx) Commit(ctx context.Context) error {
+	return t.end(ctx, true)
+}
+
+// Rollback pgx.Tx를 구현합니다. Begin으로 시작한 트랜잭션이면 세이브포인트로 되돌립니다.
+func (t *txPgx) Rollback(ctx context.Context) error {
+	return t.end(ctx, false)
+}
+
+// end 트랜잭션을 끝냅니다. pgx와 같이 이미 끝난 트랜잭션에는 pgx.ErrTxClosed를 반환합니다.
+func (t *txPgx) end(ctx context.Context, commit bool) error {
+	if t.savepoint == "" {
+		getLogger().Debug("Ignoring Commit/Rollback of the test transaction", zap.String("session", t.session.id))
+		return nil
+	}
+	if err := t.check(); err != nil {
+		return err
+	}
+	if err := t.session.end(ctx, t.savepoint, commit); err != nil {
+		return err
+	}
+	t.session.mu.Lock()
+	t.ended = true
+	t.session.mu.Unlock()
+	return nil
+}
+
+// check 트랜잭션을 사용할 수 있는지 확인합니다.
+func (t *txPgx) check() error {
+	t.session.mu.Lock()
+	defer t.session.mu.Unlock()
+
+	if t.ended {
+		return pgx.ErrTxClosed
+	}
+	if t.session.closed {
+		return errors.New("test transaction is closed")
+	}
+	return nil
+}
+
+// run 세션의 잠금을 잡고 문장 하나를 실행합니다.
+func (t *txPgx) run(ctx context.Context, fn func(conn *pgx.Conn) error) error {
+	t.session.mu.Lock()
+	defer t.session.mu.Unlock()
+
+	if t.ended {
+		return pgx.ErrTxClosed
+	}
+	if t.session.closed {
+		return errors.New("test transaction is closed")
+	}
+	return t.session.statement(ctx, func() error {
+		return fn(t.session.conn.Conn())
+	})
+}
+
+// CopyFrom pgx.Tx를 구현합니다.
+func (t *txPgx) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	var n int64
+	err := t.run(ctx, func(conn *pgx.Conn) error {
+		var err error
+		n, err = conn.CopyFrom(ctx, tableName, columnNames, rowSrc)
+		return err
+	})
+	return n, err
+}
+
+// SendBatch pgx.Tx를 구현합니다. 배치의 문장은 하나의 문장처럼 함께 취소되도록 차례로 실행되며,
+// 결과는 모두 읽어 둔 뒤 반환합니다.
+func (t *txPgx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	results := &txPgxBatch{batch: b}
+	results.err = t.run(ctx, func(conn *pgx.Conn) error {
+		for _, qq := range b.QueuedQueries {
+			rows, err := queryBuffered(ctx, conn, &t.session.mu, qq.SQL, qq.Arguments...)
+			if err != nil {
+				return err
+			}
+			results.rows = append(results.rows, rows)
+		}
+		return nil
+	})
+	return results
+}
+
+// LargeObjects pgx.Tx를 구현합니다. 대형 객체 작업은 세이브포인트 없이 테스트 트랜잭션에서 바로 실행됩니다.
+func (t *txPgx) LargeObjects() pgx.LargeObjects {
+	return t.session.tx.LargeObjects()
+}
+
+// Prepare pgx.Tx를 구현합니다.
+func (t *txPgx) Prepare(ctx context.Context, name, sql string) (*pgconn.StatementDescription, error) {
+	var sd *pgconn.StatementDescription
+	err := t.run(ctx, func(conn *pgx.Conn) error {
+		var err error
+		sd, err = conn.Prepare(ctx, name, sql)
+		return err
+	})
+	return sd, err
+}
+
+// Exec pgx.Tx를 구현합니다. 인자 없는 BEGIN, COMMIT, ROLLBACK은 세이브포인트로 바뀝니다.
+func (t *txPgx) Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error) {
+	if len(arguments) == 0 {
+		if control := txControl(sql); control != "" {
+			if err := t.check(); err != nil {
+				return pgconn.CommandTag{}, err
+			}
+			switch control {
+			case "begin":
+				_, err := t.session.begin(ctx)
+				return pgconn.NewCommandTag("BEGIN"), err
+			case "commit":
+				return pgconn.NewCommandTag("COMMIT"), t.session.end(ctx, "", true)
+			default:
+				return pgconn.NewCommandTag("ROLLBACK"), t.session.end(ctx, "", false)
+			}
+		}
+	}
+
+	var tag pgconn.CommandTag
+	err := t.run(ctx, func(conn *pgx.Conn) error {
+		var err error
+		tag, err = conn.Exec(ctx, sql, arguments...)
+		return err
+	})
+	return tag, err
+}
+
+// Query pgx.Tx를 구현합니다. 결과를 모두 읽은 뒤 반환하므로, 실패한 쿼리도 세이브포인트로 취소되고
+// rows를 닫기 전에 같은 트랜잭션에서 다른 문장을 실행할 수 있습니다.
+func (t *txPgx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	var rows *txPgxRows
+	err := t.run(ctx, func(conn *pgx.Conn) error {
+		var err error
+		rows, err = queryBuffered(ctx, conn, &t.session.mu, sql, args...)
+		return err
+	})
+	if err != nil {
+		// pgx와 같이 에러를 담은 rows를 함께 반환
+		return &txPgxRows{err: err, closed: true}, err
+	}
+	return rows, nil
+}
+
+// QueryRow pgx.Tx를 구현합니다.
+func (t *txPgx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	rows, _ := t.Query(ctx, sql, args...)
+	return txPgxRow{rows: rows}
+}
+
+// Conn pgx.Tx를 구현합니다. 반환된 연결로 실행한 문장은 세이브포인트 보호를 받지 않습니다.
+func (t *txPgx) Conn() *pgx.Conn {
+	return t.session.conn.Conn()
+}
+
+// queryBuffered 쿼리를 실행하고 결과를 모두 읽어 둡니다.
+// 결과를 변환할 때 연결의 타입 맵을 사용하므로, 연결을 보호하는 잠금(lock)을 함께 저장합니다.
+func queryBuffered(ctx context.Context, conn *pgx.Conn, lock sync.Locker, sql string, args ...any) (*txPgxRows, error) {
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buffered := &txPgxRows{
+		typeMap: conn.TypeMap(),
+		lock:    lock,
+		fields:  append([]pgconn.FieldDescription(nil), rows.FieldDescriptions()...),
+	}
+	for rows.Next() {
+		raw := rows.RawValues()
+		values := make([][]byte, len(raw))
+		for i, value := range raw {
+			if value != nil {
+				values[i] = append([]byte{}, value...)
+			}
+		}
+		buffered.rows = append(buffered.rows, values)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	buffered.tag = rows.CommandTag()
+	return buffered, nil
+}
+
+// txPgxRows 미리 읽어 둔 쿼리 결과를 pgx.Rows로 제공합니다.
+type txPgxRows struct {
+	typeMap *pgtype.Map
+	lock    sync.Locker // typeMap을 사용하는 동안 잡는 잠금 (없으면 nil)
+	fields  []pgconn.FieldDescription
+	rows    [][][]byte
+	tag     pgconn.CommandTag
+	pos     int // 다음에 읽을 행 (현재 행은 pos-1)
+	err     error
+	closed  bool
+}
+
+// Close pgx.Rows를 구현합니다.
+func (r *txPgxRows) Close() {
+	r.closed = true
+}
+
+// Err pgx.Rows를 구현합니다.
+func (r *txPgxRows) Err() error {
+	return r.err
+}
+
+// CommandTag pgx.Rows를 구현합니다.
+func (r *txPgxRows) CommandTag() pgconn.CommandTag {
+	return r.tag
+}
+
+// FieldDescriptions pgx.Rows를 구현합니다.
+func (r *txPgxRows) FieldDescriptions() []pgconn.FieldDescription {
+	return r.fields
+}
+
+// Next pgx.Rows를 구현합니다. 마지막 행 이후에는 rows를 닫습니다.
+func (r *txPgxRows) Next() bool {
+	if r.closed || r.err != nil || r.pos >= len(r.rows) {
+		r.closed = true
+		return false
+	}
+	r.pos++
+	return true
+}
+
+// Scan pgx.Rows를 구현합니다.
+func (r *txPgxRows) Scan(dest ...any) error {
+	if len(dest) == 1 {
+		if scanner, ok := dest[0].(pgx.RowScanner); ok {
+			return scanner.ScanRow(r)
+		}
+	}
+	if err := r.current(); err != nil {
+		return err
+	}
+	r.lockTypeMap()
+	err := pgx.ScanRow(r.typeMap, r.fields, r.rows[r.pos-1], dest...)
+	r.unlockTypeMap()
+	if err != nil {
+		r.err = err
+		r.closed = true
+		return err
+	}
+	return nil
+}
+
+// Values pgx.Rows를 구현합니다.
+func (r *txPgxRows) Values() ([]any, error) {
+	if err := r.current(); err != nil {
+		return nil, err
+	}
+	r.lockTypeMap()
+	defer r.unlockTypeMap()
+
+	raw := r.rows[r.pos-1]
+	values := make([]any, len(r.fields))
+	for i, field := range r.fields {
+		if raw[i] == nil {
+			continue
+		}
+		if dt, ok := r.typeMap.TypeForOID(field.DataTypeOID); ok {
+			value, err := dt.Codec.DecodeValue(r.typeMap, field.DataTypeOID, field.Format, raw[i])
+			if err != nil {
+				return nil, err
+			}
+			values[i] = value
+			continue
+		}
+		if field.Format == pgx.TextFormatCode {
+			values[i] = string(raw[i])
+		} else {
+			values[i] = append([]byte{}, raw[i]...)
+		}
+	}
+	return values, nil
+}
+
+// RawValues pgx.Rows를 구현합니다.
+func (r *txPgxRows) RawValues() [][]byte {
+	if r.pos == 0 || r.pos > len(r.rows) {
+		return nil
+	}
+	return r.rows[r.pos-1]
+}
+
+// Conn pgx.Rows를 구현합니다. 결과를 미리 읽어 두므로 연결이 없습니다.
+func (r *txPgxRows) Conn() *pgx.Conn {
+	return nil
+}
+
+// lockTypeMap 다른 문장이 연결의 타입 맵을 함께 사용하지 않도록 잠급니다.
+func (r *txPgxRows) lockTypeMap() {
+	if r.lock != nil {
+		r.lock.Lock()
+	}
+}
+
+// unlockTypeMap lockTypeMap으로 잡은 잠금을 해제합니다.
+func (r *txPgxRows) unlockTypeMap() {
+	if r.lock != nil {
+		r.lock.Unlock()
+	}
+}
+
+// current 현재 행을 읽을 수 있는지 확인합니다.
+func (r *txPgxRows) current() error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.pos == 0 || r.pos > len(r.rows) {
+		return errors.New("no current row, call Next first")
+	}
+	return nil
+}
+
+// txPgxRow QueryRow의 결과입니다.
+type txPgxRow struct {
+	rows pgx.Rows
+}
+
+// Scan pgx.Row를 구현합니다.
+func (r txPgxRow) Scan(dest ...any) error {
+	defer r.rows.Close()
+
+	if !r.rows.Next() {
+		if err := r.rows.Err(); err != nil {
+			return err
+		}
+		return pgx.ErrNoRows
+	}
+	if err := r.rows.Scan(dest...); err != nil {
+		return err
+	}
+	r.rows.Close()
+	return r.rows.Err()
+}
+
+// txPgxBatch 미리 실행한 배치의 결과를 pgx.BatchResults로 제공합니다.
+type txPgxBatch struct {
+	batch  *pgx.Batch
+	rows   []*txPgxRows // 실행에 성공한 문장의 결과
+	next   int          // 다음에 읽을 결과
+	err    error        // 배치 실행 에러 (이후의 모든 결과에 반환)
+	closed bool
+}
+
+// result 다음 문장의 결과를 반환합니다.
+func (b *txPgxBatch) result() (*txPgxRows, error) {
+	if b.closed {
+		return nil, errors.New("batch already closed")
+	}
+	if b.next >= len(b.batch.QueuedQueries) {
+		return nil, errors.New("no more results in batch")
+	}
+	b.next++
+	if b.next > len(b.rows) {
+		return nil, b.err
+	}
+	return b.rows[b.next-1], nil
+}
+
+// Exec pgx.BatchResults를 구현합니다.
+func (b *txPgxBatch) Exec() (pgconn.CommandTag, error) {
+	rows, err := b.result()
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return rows.tag, nil
+}
+
+// Query pgx.BatchResults를 구현합니다.
+func (b *txPgxBatch) Query() (pgx.Rows, error) {
+	rows, err := b.result()
+	if err != nil {
+		return &txPgxRows{err: err, closed: true}, err
+	}
+	return rows, nil
+}
+
+// QueryRow pgx.BatchResults를 구현합니다.
+func (b *txPgxBatch) QueryRow() pgx.Row {
+	rows, _ := b.Query()
+	return txPgxRow{rows: rows}
+}
+
+// Close pgx.BatchResults를 구현합니다. pgx와 같이 읽지 않은 결과의 콜백(QueuedQuery.Exec 등)을 호출합니다.
+func (b *txPgxBatch) Close() error {
+	if b.closed {
+		return b.err
+	}
+	for b.next < len(b.batch.QueuedQueries) {
+		qq := b.batch.QueuedQueries[b.next]
+		if qq.Fn == nil {
+			if _, err := b.Exec(); err != nil {
+				break
+			}
+			continue
+		}
+		if err := qq.Fn(b); err != nil {
+			if b.err == nil {
+				b.err = err
+			}
+			break
+		}
+	}
+	b.closed = true
+	return b.err
+}
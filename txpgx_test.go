@@ -0,0 +1,114 @@
+package pgtestkit
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// newTestPgxRows 텍스트 형식의 int4, text 열을 가진 결과를 만듭니다.
+func newTestPgxRows(rows ...[][]byte) *txPgxRows {
+	return &txPgxRows{
+		typeMap: pgtype.NewMap(),
+		fields: []pgconn.FieldDescription{
+			{Name: "id", DataTypeOID: pgtype.Int4OID, Format: pgx.TextFormatCode},
+			{Name: "name", DataTypeOID: pgtype.TextOID, Format: pgx.TextFormatCode},
+		},
+		rows: rows,
+		tag:  pgconn.NewCommandTag("SELECT 2"),
+	}
+}
+
+func TestTxPgxRows(t *testing.T) {
+	rows := newTestPgxRows([][]byte{[]byte("1"), []byte("Ann")}, [][]byte{[]byte("2"), nil})
+
+	if err := rows.Scan(new(int), new(string)); err == nil {
+		t.Error("Expected Scan before Next to fail")
+	}
+
+	var ids []int
+	var names []*string
+	for rows.Next() {
+		var id int
+		var name *string
+		if err := rows.Scan(&id, &name); err != nil {
+			t.Fatalf("Failed to scan: %v", err)
+		}
+		ids = append(ids, id)
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []int{1, 2}) || names[0] == nil || *names[0] != "Ann" || names[1] != nil {
+		t.Errorf("Unexpected rows: ids %v, names %v", ids, names)
+	}
+	if rows.CommandTag().RowsAffected() != 2 {
+		t.Errorf("Expected command tag SELECT 2, got %s", rows.CommandTag())
+	}
+
+	values := newTestPgxRows([][]byte{[]byte("3"), []byte("Bob")})
+	values.Next()
+	got, err := values.Values()
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	if !reflect.DeepEqual(got, []any{int32(3), "Bob"}) {
+		t.Errorf("Expected [3 Bob], got %v", got)
+	}
+
+	// pgx의 도우미 함수와 함께 사용할 수 있어야 함
+	collected, err := pgx.CollectRows(newTestPgxRows([][]byte{[]byte("4"), []byte("Cy")}), pgx.RowToMap)
+	if err != nil {
+		t.Fatalf("Failed to collect rows: %v", err)
+	}
+	if len(collected) != 1 || collected[0]["name"] != "Cy" {
+		t.Errorf("Unexpected collected rows: %v", collected)
+	}
+}
+
+func TestTxPgxRow(t *testing.T) {
+	var id int
+	var name string
+	if err := (txPgxRow{rows: newTestPgxRows()}).Scan(&id, &name); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("Expected pgx.ErrNoRows, got %v", err)
+	}
+	row := txPgxRow{rows: newTestPgxRows([][]byte{[]byte("5"), []byte("Dee")})}
+	if err := row.Scan(&id, &name); err != nil || id != 5 || name != "Dee" {
+		t.Errorf("Expected 5 Dee, got %d %s (%v)", id, name, err)
+	}
+}
+
+func TestTxPgxBatch(t *testing.T) {
+	failure := errors.New("duplicate key")
+	b := &pgx.Batch{}
+	b.Queue("INSERT 1")
+	var tag pgconn.CommandTag
+	b.Queue("INSERT 2").Exec(func(ct pgconn.CommandTag) error {
+		tag = ct
+		return nil
+	})
+	b.Queue("INSERT 3")
+
+	// 두 번째 문장까지 성공하고 세 번째 문장이 실패한 배치
+	inserted := &txPgxRows{tag: pgconn.NewCommandTag("INSERT 0 1")}
+	results := &txPgxBatch{batch: b, rows: []*txPgxRows{inserted, inserted}, err: failure}
+
+	if ct, err := results.Exec(); err != nil || ct.String() != "INSERT 0 1" {
+		t.Errorf("Expected the first result, got %s (%v)", ct, err)
+	}
+	// Close는 남은 콜백을 호출하고 배치의 에러를 반환함
+	if err := results.Close(); !errors.Is(err, failure) {
+		t.Errorf("Expected the batch error, got %v", err)
+	}
+	if tag.String() != "INSERT 0 1" {
+		t.Errorf("Expected the callback to receive the command tag, got %q", tag)
+	}
+	if _, err := results.Exec(); err == nil {
+		t.Error("Expected reading a closed batch to fail")
+	}
+}
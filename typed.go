@@ -0,0 +1,188 @@
+package pgtestkit
+
+import (
+	"context"
+	"fmt"
+)
+
+// Connector 타입이 지정된 클라이언트를 반환하는 데이터베이스 커넥터 인터페이스입니다.
+// DBConnector와 같은 역할을 하지만, Connect가 interface{} 대신 T를 반환하므로
+// 테스트 코드에서 타입 단언이 필요하지 않습니다.
+type Connector[T any] interface {
+	// Connect 데이터베이스에 연결하고 클라이언트를 반환합니다.
+	Connect(connString string) (T, error)
+
+	// Close 데이터베이스 연결을 종료합니다.
+	Close() error
+
+	// Reset 데이터베이스를 초기 상태로 되돌립니다.
+	Reset() error
+}
+
+// ContextConnector 컨텍스트를 지원하는 Connector입니다.
+type ContextConnector[T any] interface {
+	Connector[T]
+
+	// ConnectContext 컨텍스트를 지원하는 Connect입니다.
+	ConnectContext(ctx context.Context, connString string) (T, error)
+
+	// ResetContext 컨텍스트를 지원하는 Reset입니다.
+	ResetContext(ctx context.Context) error
+}
+
+// TypedDBClient 타입이 지정된 클라이언트를 가진 DBClient입니다.
+// DBName, ConnectionString, Close, CloseContext 등은 내장된 DBClient의 것을 그대로 사용하며,
+// NewTestHelper에는 DBClient 필드를 전달하면 됩니다.
+type TypedDBClient[T any] struct {
+	*DBClient
+	Client T // 타입이 지정된 DB 클라이언트 (DBClient.Client를 가림)
+}
+
+// CreateTestDBOf 타입이 지정된 커넥터를 사용하여 테스트 데이터베이스를 생성합니다.
+//
+// 사용 예시:
+//
+//	// GORMConnector는 Connector[*gorm.DB]를 구현한다고 가정합니다.
+//	dbClient, err := pgtestkit.CreateTestDBOf[*gorm.DB](&GORMConnector{})
+//	if err != nil {
+//		t.Fatalf("Failed to create test DB: %v", err)
+//	}
+//	defer dbClient.Close()
+//
+//	gormDB := dbClient.Client // *gorm.DB
+func CreateTestDBOf[T any](connector Connector[T], opts ...Option) (*TypedDBClient[T], error) {
+	return CreateTestDBOfContext(context.Background(), connector, opts...)
+}
+
+// CreateTestDBOfContext 컨텍스트를 지원하는 CreateTestDBOf입니다.
+func CreateTestDBOfContext[T any](ctx context.Context, connector Connector[T], opts ...Option) (*TypedDBClient[T], error) {
+	if connector == nil {
+		err := fmt.Errorf("Connector must not be nil")
+		logError("Invalid argument", err)
+		return nil, err
+	}
+
+	adapter := &connectorAdapter[T]{connector: connector}
+	dbClient, err := CreateTestDBContext(ctx, adapter, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TypedDBClient[T]{
+		DBClient: dbClient,
+		Client:   adapter.client,
+	}, nil
+}
+
+// AsTyped 기존 DBClient를 타입이 지정된 클라이언트로 변환합니다.
+// DBClient.Client가 T가 아니면 에러를 반환합니다.
+func AsTyped[T any](dbClient *DBClient) (*TypedDBClient[T], error) {
+	if dbClient == nil {
+		return nil, fmt.Errorf("DBClient must not be nil")
+	}
+
+	client, ok := dbClient.Client.(T)
+	if !ok {
+		return nil, fmt.Errorf("expected client of type %T, got %T", *new(T), dbClient.Client)
+	}
+	return &TypedDBClient[T]{
+		DBClient: dbClient,
+		Client:   client,
+	}, nil
+}
+
+// TypedConnector 기존 DBConnector를 타입이 지정된 Connector로 감쌉니다.
+// Connect가 반환한 클라이언트가 T가 아니면 에러를 반환합니다.
+//
+// 사용 예시:
+//
+//	dbClient, err := pgtestkit.CreateTestDBOf(pgtestkit.TypedConnector[*gorm.DB](&GORMConnector{}))
+func TypedConnector[T any](connector DBConnector) Connector[T] {
+	return &typedConnector[T]{connector: connector}
+}
+
+// typedConnector DBConnector를 Connector[T]로 변환하는 어댑터입니다.
+type typedConnector[T any] struct {
+	connector DBConnector
+}
+
+// Connect 데이터베이스에 연결하고 클라이언트를 T로 변환합니다.
+func (c *typedConnector[T]) Connect(connString string) (T, error) {
+	return c.ConnectContext(context.Background(), connString)
+}
+
+// ConnectContext 컨텍스트를 지원하는 Connect입니다.
+func (c *typedConnector[T]) ConnectContext(ctx context.Context, connString string) (T, error) {
+	var zero T
+	client, err := connectContext(ctx, c.connector, connString)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := client.(T)
+	if !ok {
+		return zero, fmt.Errorf("expected client of type %T, got %T", zero, client)
+	}
+	return typed, nil
+}
+
+// Close 데이터베이스 연결을 종료합니다.
+func (c *typedConnector[T]) Close() error {
+	return c.connector.Close()
+}
+
+// Reset 데이터베이스를 초기 상태로 되돌립니다.
+func (c *typedConnector[T]) Reset() error {
+	return c.connector.Reset()
+}
+
+// ResetContext 컨텍스트를 지원하는 Reset입니다.
+func (c *typedConnector[T]) ResetContext(ctx context.Context) error {
+	return resetContext(ctx, c.connector)
+}
+
+// connectorAdapter Connector[T]를 기존 DBConnector로 변환하는 어댑터입니다.
+type connectorAdapter[T any] struct {
+	connector Connector[T]
+	client    T // 마지막으로 연결된 타입이 지정된 클라이언트
+}
+
+// Connect 데이터베이스에 연결하고 클라이언트를 반환합니다.
+func (a *connectorAdapter[T]) Connect(connString string) (interface{}, error) {
+	return a.ConnectContext(context.Background(), connString)
+}
+
+// ConnectContext 컨텍스트를 지원하는 Connect입니다.
+func (a *connectorAdapter[T]) ConnectContext(ctx context.Context, connString string) (interface{}, error) {
+	var (
+		client T
+		err    error
+	)
+	if c, ok := a.connector.(ContextConnector[T]); ok {
+		client, err = c.ConnectContext(ctx, connString)
+	} else {
+		client, err = a.connector.Connect(connString)
+	}
+	if err != nil {
+		return nil, err
+	}
+	a.client = client
+	return client, nil
+}
+
+// Close 데이터베이스 연결을 종료합니다.
+func (a *connectorAdapter[T]) Close() error {
+	return a.connector.Close()
+}
+
+// Reset 데이터베이스를 초기 상태로 되돌립니다.
+func (a *connectorAdapter[T]) Reset() error {
+	return a.connector.Reset()
+}
+
+// ResetContext 컨텍스트를 지원하는 Reset입니다.
+func (a *connectorAdapter[T]) ResetContext(ctx context.Context) error {
+	if c, ok := a.connector.(ContextConnector[T]); ok {
+		return c.ResetContext(ctx)
+	}
+	return a.connector.Reset()
+}
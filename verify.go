@@ -0,0 +1,149 @@
+package pgtestkit
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// VerifyMigrations 모든 down 마이그레이션이 up 마이그레이션을 정확히 되돌리는지 검증하고, 실패하면 테스트를 중단합니다.
+// 자세한 동작은 VerifyMigrationsContext를 참고하세요.
+//
+// 사용 예시:
+//
+//	func TestMigrationsRoundTrip(t *testing.T) {
+//		pgtestkit.VerifyMigrations(t, os.DirFS("migrations"))
+//	}
+func VerifyMigrations(t testing.TB, fsys fs.FS, opts ...MigrateOption) {
+	t.Helper()
+	if err := VerifyMigrationsContext(context.Background(), fsys, opts...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// VerifyMigrationsContext 임시 데이터베이스에 모든 마이그레이션을 적용하며 버전마다 스키마 스냅샷을 기록한 뒤,
+// 최신 버전부터 한 단계씩 down과 up을 반복하여 스키마가 이전 상태로 돌아오는지 확인합니다.
+// down 후의 스키마가 up 이전과 다르거나, 다시 up한 스키마가 처음 up한 것과 다르면 스키마 차이를 담은 오류를 반환합니다.
+// 모든 마이그레이션에 down 파일이 있어야 하며, 임시 데이터베이스는 검증이 끝나면 삭제됩니다.
+func VerifyMigrationsContext(ctx context.Context, fsys fs.FS, opts ...MigrateOption) error {
+	cfg, err := newMigrateConfig(opts)
+	if err != nil {
+		return err
+	}
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		return err
+	}
+	for _, m := range migrations {
+		if m.DownFile == "" {
+			return fmt.Errorf("migration %s has no down file", m.UpFile)
+		}
+	}
+
+	db, cleanup, err := createScratchDatabase(ctx, "verify")
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	logger := getLogger()
+	logger.Info("Verifying migrations round trip", zap.Int("migrations", len(migrations)))
+
+	// 마이그레이션 테이블도 스냅샷에 포함되므로 첫 스냅샷 전에 생성
+	if _, err := appliedMigrations(ctx, db, cfg.table); err != nil {
+		return err
+	}
+
+	// 버전별 스키마: snapshots[i]는 migrations[i]를 적용하기 직전의 스키마
+	snapshots := make([]string, len(migrations)+1)
+	if snapshots[0], err = captureSchema(ctx, db); err != nil {
+		return err
+	}
+	for i, m := range migrations {
+		if err := runMigration(ctx, db, cfg.table, m, true); err != nil {
+			return err
+		}
+		if snapshots[i+1], err = captureSchema(ctx, db); err != nil {
+			return err
+		}
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		logger.Debug("Verifying migration round trip", zap.String("file", m.UpFile))
+
+		// down은 up 이전의 스키마로 되돌려야 함
+		if err := runMigration(ctx, db, cfg.table, m, false); err != nil {
+			return err
+		}
+		below, err := captureSchema(ctx, db)
+		if err != nil {
+			return err
+		}
+		if diff := diffLines(snapshots[i], below); diff != "" {
+			return fmt.Errorf("%s does not restore the schema from before %s (- expected, + actual):\n%s",
+				m.DownFile, m.UpFile, diff)
+		}
+
+		// 다시 up하면 처음과 같은 스키마가 되어야 함
+		if err := runMigration(ctx, db, cfg.table, m, true); err != nil {
+			return err
+		}
+		again, err := captureSchema(ctx, db)
+		if err != nil {
+			return err
+		}
+		if diff := diffLines(snapshots[i+1], again); diff != "" {
+			return fmt.Errorf("re-applying %s after %s produces a different schema (- expected, + actual):\n%s",
+				m.UpFile, m.DownFile, diff)
+		}
+
+		// 다음 단계를 위해 다시 내려감
+		if err := runMigration(ctx, db, cfg.table, m, false); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("Migrations round trip verified")
+	return nil
+}
+
+// createScratchDatabase 검증 등에 사용할 빈 데이터베이스를 만들고 연결을 반환합니다.
+// 반환된 cleanup은 연결을 닫고 데이터베이스를 삭제합니다.
+func createScratchDatabase(ctx context.Context, hint string) (*sql.DB, func(), error) {
+	serverMutex.Lock()
+	if !serverStarted || serverStopped {
+		err := serverNotRunningError()
+		serverMutex.Unlock()
+		logError("Cannot create scratch database", err)
+		return nil, nil, err
+	}
+	name := generateTestDBName(hint)
+	err := createDatabase(ctx, name, "")
+	serverMutex.Unlock()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create scratch database: %w", err)
+	}
+
+	drop := func() {
+		serverMutex.Lock()
+		defer serverMutex.Unlock()
+		if err := dropDatabase(context.WithoutCancel(ctx), name); err != nil {
+			logError("Failed to drop scratch database", err)
+		}
+	}
+
+	db, err := sql.Open("pgx", getConnectionString(name))
+	if err != nil {
+		drop()
+		return nil, nil, fmt.Errorf("failed to connect to scratch database: %w", err)
+	}
+	return db, func() {
+		db.Close()
+		drop()
+	}, nil
+}